@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// sessionURLs 需要持久化 Cookie 的站点
+var sessionURLs = []string{
+	"https://jwxt.bistu.edu.cn/jwapp/",
+	"https://wxjw.bistu.edu.cn/authserver/",
+}
+
+// savedCookie 持久化的 Cookie（cookiejar 只暴露名称和值）
+type savedCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SaveSession 将当前登录状态的 Cookie 保存到文件，供 watch / serve 等长期运行的命令复用
+func (c *Client) SaveSession(filename string) error {
+	session := make(map[string][]savedCookie)
+	for _, raw := range sessionURLs {
+		u, _ := url.Parse(raw)
+		for _, ck := range c.HTTP.Jar.Cookies(u) {
+			session[raw] = append(session[raw], savedCookie{Name: ck.Name, Value: ck.Value})
+		}
+	}
+	if len(session) == 0 {
+		return fmt.Errorf("当前没有可保存的登录状态")
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("序列化会话失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o700); err != nil {
+		return fmt.Errorf("创建会话目录失败: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return fmt.Errorf("保存会话失败: %w", err)
+	}
+	return nil
+}
+
+// LoadSession 从文件恢复登录状态，Cookie 是否仍然有效需由调用方请求验证
+func (c *Client) LoadSession(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("读取会话失败: %w", err)
+	}
+	var session map[string][]savedCookie
+	if err := json.Unmarshal(data, &session); err != nil {
+		return fmt.Errorf("会话文件已损坏: %w", err)
+	}
+
+	for raw, saved := range session {
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		cookies := make([]*http.Cookie, 0, len(saved))
+		for _, s := range saved {
+			cookies = append(cookies, &http.Cookie{Name: s.Name, Value: s.Value, Path: "/"})
+		}
+		c.HTTP.Jar.SetCookies(u, cookies)
+	}
+	return nil
+}
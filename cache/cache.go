@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// DefaultTTL 缓存默认有效期
+const DefaultTTL = 6 * time.Hour
+
+// ErrNotFound 缓存不存在
+var ErrNotFound = errors.New("本地没有缓存的课表")
+
+// Entry 一个学生一个学期的缓存课表
+type Entry struct {
+	StudentID string                   `json:"studentId"`
+	UserName  string                   `json:"userName,omitempty"`
+	Term      string                   `json:"term"`
+	StartDate time.Time                `json:"startDate,omitempty"`
+	FetchedAt time.Time                `json:"fetchedAt"`
+	Raw       []map[string]interface{} `json:"raw"`
+	Courses   []schedule.Course        `json:"courses"`
+
+	// RawNotArranged 和 Unscheduled 为未安排时间的课程（实践课等）
+	RawNotArranged []map[string]interface{} `json:"rawNotArranged,omitempty"`
+	Unscheduled    []schedule.Unscheduled   `json:"unscheduled,omitempty"`
+
+	// Rejected 星期或节次无法识别、未写入 Courses 的记录
+	Rejected []schedule.Rejected `json:"rejected,omitempty"`
+}
+
+// Data 返回条目中保存的原始记录
+func (e *Entry) Data() *schedule.ScheduleData {
+	return &schedule.ScheduleData{Arranged: e.Raw, NotArranged: e.RawNotArranged}
+}
+
+// Age 返回缓存距今的时长
+func (e *Entry) Age(now time.Time) time.Duration {
+	return now.Sub(e.FetchedAt)
+}
+
+// Stale 判断缓存是否已超过有效期
+func (e *Entry) Stale(ttl time.Duration, now time.Time) bool {
+	return e.Age(now) > ttl
+}
+
+// Store 本地缓存目录，按 <学号>/<学期>.json 存放
+type Store struct {
+	Dir string
+}
+
+// latest 记录最近一次写入的缓存，供 today / status 等命令默认读取
+type latest struct {
+	StudentID string `json:"studentId"`
+	Term      string `json:"term"`
+}
+
+// DefaultDir 返回默认缓存目录（如 ~/.cache/bistu-wakeup）
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("无法确定缓存目录: %w", err)
+	}
+	return filepath.Join(dir, "bistu-wakeup"), nil
+}
+
+// Open 打开缓存目录，dir 为空时使用默认目录
+func Open(dir string) (*Store, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return &Store{Dir: dir}, nil
+}
+
+var safeNameRe = regexp.MustCompile(`[^0-9A-Za-z_.-]`)
+
+func (s *Store) path(studentID, term string) string {
+	return filepath.Join(s.Dir, safeNameRe.ReplaceAllString(studentID, "_"),
+		safeNameRe.ReplaceAllString(term, "_")+".json")
+}
+
+// SessionFile 返回保存登录会话的文件路径
+func (s *Store) SessionFile() string {
+	return filepath.Join(s.Dir, "session.json")
+}
+
+// Load 读取指定学号、学期的缓存
+func (s *Store) Load(studentID, term string) (*Entry, error) {
+	var e Entry
+	if err := readJSON(s.path(studentID, term), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Save 写入缓存并记为最近一次使用，课表内容有变化时同时追加历史版本
+func (s *Store) Save(e *Entry) error {
+	if err := writeJSON(s.path(e.StudentID, e.Term), e); err != nil {
+		return err
+	}
+	if err := s.record(e); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(s.Dir, "latest.json"), latest{StudentID: e.StudentID, Term: e.Term})
+}
+
+// Latest 读取最近一次写入的缓存
+func (s *Store) Latest() (*Entry, error) {
+	var l latest
+	if err := readJSON(filepath.Join(s.Dir, "latest.json"), &l); err != nil {
+		return nil, err
+	}
+	return s.Load(l.StudentID, l.Term)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("读取缓存失败: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("缓存文件已损坏 (%s): %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON 先写临时文件再重命名，避免中断时留下半个文件
+func writeJSON(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("序列化缓存失败: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("写入缓存失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("写入缓存失败: %w", err)
+	}
+	return nil
+}
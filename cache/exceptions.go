@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// exceptionsPath 单次调课记录：<学号>/<学期>.exceptions.json
+// 与课表缓存分开保存，重新获取课表不会覆盖
+func (s *Store) exceptionsPath(studentID, term string) string {
+	return strings.TrimSuffix(s.path(studentID, term), ".json") + ".exceptions.json"
+}
+
+// Exceptions 读取某学期的停课、调课和补课记录，没有记录时返回空列表
+func (s *Store) Exceptions(studentID, term string) ([]schedule.Exception, error) {
+	var list []schedule.Exception
+	err := readJSON(s.exceptionsPath(studentID, term), &list)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return list, err
+}
+
+// ExceptionsModTime 返回调课记录最后修改的时间，没有记录时返回零值
+func (s *Store) ExceptionsModTime(studentID, term string) time.Time {
+	info, err := os.Stat(s.exceptionsPath(studentID, term))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// SaveExceptions 保存某学期的调课记录
+func (s *Store) SaveExceptions(studentID, term string, list []schedule.Exception) error {
+	return writeJSON(s.exceptionsPath(studentID, term), list)
+}
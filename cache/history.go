@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// Version 某一学期课表的一个历史版本
+type Version struct {
+	Seq       int               `json:"-"`
+	Hash      string            `json:"hash"`
+	FetchedAt time.Time         `json:"fetchedAt"`
+	Courses   []schedule.Course `json:"courses"`
+}
+
+// ShortHash 返回哈希前 8 位，用于展示和引用
+func (v *Version) ShortHash() string {
+	if len(v.Hash) < 8 {
+		return v.Hash
+	}
+	return v.Hash[:8]
+}
+
+// HashCourses 计算课表内容哈希，与课程顺序无关
+func HashCourses(courses []schedule.Course) string {
+	sorted := append([]schedule.Course(nil), courses...)
+	for i, c := range sorted {
+		// 只比较课表本身，原始记录顺序和附加信息的变化不算内容变化
+		sorted[i] = schedule.Course{
+			Name: c.Name, DayOfWeek: c.DayOfWeek, BeginSection: c.BeginSection, EndSection: c.EndSection,
+			Teacher: c.Teacher, Location: c.Location, Weeks: c.Weeks,
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return courseSortKey(sorted[i]) < courseSortKey(sorted[j])
+	})
+	data, _ := json.Marshal(sorted)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func courseSortKey(c schedule.Course) string {
+	return strings.Join([]string{
+		c.DayOfWeek, c.BeginSection, c.EndSection, c.Name, c.Weeks, c.Location, c.Teacher,
+	}, "\x00")
+}
+
+// historyDir 历史版本目录：<学号>/<学期>.history/
+func (s *Store) historyDir(studentID, term string) string {
+	return strings.TrimSuffix(s.path(studentID, term), ".json") + ".history"
+}
+
+// record 内容与最新版本不同时追加一个历史版本，已有版本从不修改
+func (s *Store) record(e *Entry) error {
+	hash := HashCourses(e.Courses)
+	versions, err := s.History(e.StudentID, e.Term)
+	if err != nil {
+		return err
+	}
+	if n := len(versions); n > 0 && versions[n-1].Hash == hash {
+		return nil
+	}
+
+	v := Version{Hash: hash, FetchedAt: e.FetchedAt, Courses: e.Courses}
+	name := fmt.Sprintf("%d-%s.json", e.FetchedAt.Unix(), v.ShortHash())
+	path := filepath.Join(s.historyDir(e.StudentID, e.Term), name)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	return writeJSON(path, v)
+}
+
+// History 列出某学期的全部历史版本（按获取时间排序，Seq 从 1 开始）
+func (s *Store) History(studentID, term string) ([]*Version, error) {
+	dir := s.historyDir(studentID, term)
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取历史版本失败: %w", err)
+	}
+
+	var versions []*Version
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		var v Version
+		if err := readJSON(filepath.Join(dir, f.Name()), &v); err != nil {
+			return nil, err
+		}
+		versions = append(versions, &v)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].FetchedAt.Before(versions[j].FetchedAt)
+	})
+	for i, v := range versions {
+		v.Seq = i + 1
+	}
+	return versions, nil
+}
+
+// FindVersion 按序号、哈希前缀或 "latest" 查找版本
+func FindVersion(versions []*Version, ref string) (*Version, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("没有历史版本")
+	}
+	if ref == "latest" {
+		return versions[len(versions)-1], nil
+	}
+	if n, err := strconv.Atoi(ref); err == nil && len(ref) < 4 {
+		if n < 1 || n > len(versions) {
+			return nil, fmt.Errorf("版本序号超出范围: %d（共 %d 个版本）", n, len(versions))
+		}
+		return versions[n-1], nil
+	}
+
+	var found *Version
+	for _, v := range versions {
+		if strings.HasPrefix(v.Hash, ref) {
+			if found != nil {
+				return nil, fmt.Errorf("哈希前缀 %q 对应多个版本，请提供更长的前缀", ref)
+			}
+			found = v
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("找不到版本 %q", ref)
+	}
+	return found, nil
+}
+
+// VersionAt 返回在指定时刻生效的版本（该时刻之前获取的最新版本）
+func VersionAt(versions []*Version, at time.Time) (*Version, error) {
+	var found *Version
+	for _, v := range versions {
+		if v.FetchedAt.After(at) {
+			break
+		}
+		found = v
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%s 之前没有记录的版本", at.Format("2006-01-02"))
+	}
+	return found, nil
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func TestHashCourses(t *testing.T) {
+	math := schedule.Course{Name: "高数", DayOfWeek: "3", BeginSection: "3", EndSection: "4", Teacher: "张三", Location: "3-101", Weeks: "1-16"}
+	english := schedule.Course{Name: "英语", DayOfWeek: "1", BeginSection: "1", EndSection: "2", Teacher: "李四", Location: "1-101", Weeks: "1-16"}
+	base := HashCourses([]schedule.Course{math, english})
+
+	withMeta := math
+	withMeta.Code, withMeta.Credits, withMeta.Record = "B100", 4, 7
+	moved := math
+	moved.Location = "5-203"
+
+	tests := []struct {
+		name    string
+		courses []schedule.Course
+		same    bool
+	}{
+		{"顺序不同", []schedule.Course{english, math}, true},
+		{"附加信息和记录编号不同", []schedule.Course{withMeta, english}, true},
+		{"地点变化", []schedule.Course{moved, english}, false},
+		{"少一门课", []schedule.Course{math}, false},
+		{"重复记录", []schedule.Course{math, english, math}, false},
+	}
+	for _, tt := range tests {
+		if got := HashCourses(tt.courses) == base; got != tt.same {
+			t.Errorf("%s: same hash = %v, want %v", tt.name, got, tt.same)
+		}
+	}
+}
+
+func TestFindVersion(t *testing.T) {
+	versions := []*Version{
+		{Seq: 1, Hash: "abc12345ffff"},
+		{Seq: 2, Hash: "abd99999ffff"},
+		{Seq: 3, Hash: "1234abcdffff"},
+	}
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"latest", 3},
+		{"1", 1},
+		{"3", 3},
+		{"abc", 1},
+		{"abd9", 2},
+		{"1234", 3}, // 四位以上的数字按哈希前缀处理
+		{"1234abcdffff", 3},
+	}
+	for _, tt := range tests {
+		v, err := FindVersion(versions, tt.ref)
+		if err != nil || v.Seq != tt.want {
+			t.Errorf("FindVersion(%q) = %+v, %v; want version %d", tt.ref, v, err, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"0", "4", "ab", "fff", "zzz"} {
+		if v, err := FindVersion(versions, ref); err == nil {
+			t.Errorf("FindVersion(%q) = %+v, want error", ref, v)
+		}
+	}
+	if v, err := FindVersion(nil, "latest"); err == nil {
+		t.Errorf("FindVersion(nil) = %+v, want error", v)
+	}
+}
+
+func TestVersionAt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 12, 0, 0, 0, schedule.TZ) }
+	versions := []*Version{{Seq: 1, FetchedAt: day(1)}, {Seq: 2, FetchedAt: day(10)}}
+	tests := []struct {
+		at   time.Time
+		want int
+	}{
+		{day(1), 1},
+		{day(5), 1},
+		{day(10), 2},
+		{day(20), 2},
+	}
+	for _, tt := range tests {
+		v, err := VersionAt(versions, tt.at)
+		if err != nil || v.Seq != tt.want {
+			t.Errorf("VersionAt(%s) = %+v, %v; want version %d", tt.at.Format("01-02"), v, err, tt.want)
+		}
+	}
+	if v, err := VersionAt(versions, day(1).Add(-time.Hour)); err == nil {
+		t.Errorf("VersionAt before first version = %+v, want error", v)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// conflictReport JSON 输出中的一条冲突，附带涉及的课程和原始记录
+type conflictReport struct {
+	schedule.Conflict
+	Courses [2]schedule.Course        `json:"courses"`
+	Raw     [2]map[string]interface{} `json:"raw,omitempty"`
+}
+
+func runConflicts(args []string) error {
+	fs := newFlagSet("conflicts")
+	from := fs.String("from", cacheSource, "课表来源：@cache 或导出的 CSV / JSON、原始数据文件")
+	asJSON := fs.Bool("json", false, "以 JSON 输出冲突和跨校区提醒")
+	showRaw := fs.Bool("raw", false, "同时打印冲突课程对应的原始记录")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := loadSource(*from)
+	if err != nil {
+		return err
+	}
+	conflicts := schedule.FindConflicts(t.Courses)
+	travel := schedule.FindTravelWarnings(t.Courses, t.Locations)
+	raw := rawRecords(*from)
+
+	if *asJSON {
+		reports := make([]conflictReport, 0, len(conflicts))
+		for _, c := range conflicts {
+			r := conflictReport{Conflict: c, Courses: [2]schedule.Course{t.Courses[c.A], t.Courses[c.B]}}
+			r.Raw[0] = rawRecord(raw, t.Courses[c.A])
+			r.Raw[1] = rawRecord(raw, t.Courses[c.B])
+			reports = append(reports, r)
+		}
+		return printJSON(struct {
+			Conflicts []conflictReport         `json:"conflicts"`
+			Travel    []schedule.TravelWarning `json:"travel"`
+		}{reports, travel})
+	}
+
+	if len(conflicts) == 0 && len(travel) == 0 {
+		fmt.Printf("    %s 没有发现时间冲突，也没有来不及赶路的课间\n\n", green("✓"))
+		return nil
+	}
+	if len(conflicts) > 0 {
+		printConflicts(t.Courses, conflicts, raw != nil)
+	}
+	if len(travel) > 0 {
+		printTravelWarnings(t.Courses, travel)
+	}
+	if *showRaw && raw == nil && len(conflicts) > 0 {
+		fmt.Printf("    %s\n\n", dim("该来源不含原始记录，--raw 仅适用于 @cache 或原始数据文件"))
+	}
+	if *showRaw && raw != nil {
+		for _, c := range conflicts {
+			for _, i := range []int{c.A, c.B} {
+				printRawRecord(t.Courses[i], rawRecord(raw, t.Courses[i]))
+			}
+		}
+	}
+	return nil
+}
+
+// printConflicts 以彩色文本输出时间冲突
+// withRecords 表示课表来自教务系统的原始数据（缓存或原始数据文件），此时指出对应的原始记录
+func printConflicts(courses []schedule.Course, conflicts []schedule.Conflict, withRecords bool) {
+	fmt.Printf("    %s 发现 %s 处时间冲突:\n", yellow("⚠"), bold(fmt.Sprintf("%d", len(conflicts))))
+	for _, c := range conflicts {
+		fmt.Printf("      %s %s\n", yellow("!"), c.Describe(courses))
+		for _, i := range []int{c.A, c.B} {
+			fmt.Printf("          %s\n", dim(recordLabel(courses[i], withRecords)))
+		}
+	}
+	fmt.Println()
+}
+
+// printTravelWarnings 输出课间来不及赶到下一个校区或教学楼的提醒
+func printTravelWarnings(courses []schedule.Course, warnings []schedule.TravelWarning) {
+	fmt.Printf("    %s %s 处课间可能来不及赶路:\n", yellow("⚠"), bold(fmt.Sprintf("%d", len(warnings))))
+	for _, w := range warnings {
+		fmt.Printf("      %s %s\n", yellow("→"), w.Describe(courses))
+	}
+	fmt.Println()
+}
+
+// recordLabel 描述冲突的课程，withRecords 为 true 时注明来自哪条原始记录
+// 导出的 CSV / JSON 没有原始数据，其中的记录编号无从对照，不再显示
+func recordLabel(c schedule.Course, withRecords bool) string {
+	desc := fmt.Sprintf("%s 周%s %s-%s节 第%s周 %s", c.Name, c.DayOfWeek, c.BeginSection, c.EndSection, c.Weeks, c.Location)
+	if withRecords && c.Record > 0 {
+		return fmt.Sprintf("原始记录 #%d: %s", c.Record, desc)
+	}
+	return desc
+}
+
+func printRawRecord(c schedule.Course, raw map[string]interface{}) {
+	if raw == nil {
+		return
+	}
+	data, err := json.MarshalIndent(raw, "      ", "  ")
+	if err != nil {
+		return
+	}
+	fmt.Printf("    %s\n      %s\n\n", bold(fmt.Sprintf("原始记录 #%d（%s）", c.Record, c.Name)), data)
+}
+
+// rawRecords 读取课表来源对应的原始记录，CSV 等不含原始数据的来源返回 nil
+func rawRecords(spec string) []map[string]interface{} {
+	if spec == cacheSource {
+		store, err := cache.Open("")
+		if err != nil {
+			return nil
+		}
+		entry, err := store.Latest()
+		if err != nil {
+			return nil
+		}
+		return entry.Raw
+	}
+	if _, err := os.Stat(spec); err != nil {
+		return nil
+	}
+	dump, err := schedule.LoadRaw(spec)
+	if err != nil {
+		return nil
+	}
+	return dump.Items
+}
+
+func rawRecord(raw []map[string]interface{}, c schedule.Course) map[string]interface{} {
+	if c.Record < 1 || c.Record > len(raw) {
+		return nil
+	}
+	return raw[c.Record-1]
+}
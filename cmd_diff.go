@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func runDiff(args []string) error {
+	fs := newFlagSet("diff")
+	asJSON := fs.Bool("json", false, "以 JSON 输出变更列表")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("需要指定两个课表（文件路径或 %s）", cacheSource)
+	}
+
+	old, err := loadSource(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cur, err := loadSource(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	changes := schedule.Diff(old.Courses, cur.Courses)
+	if *asJSON {
+		return printJSON(changes)
+	}
+	printChanges(changes)
+	return nil
+}
+
+// printChanges 以彩色文本输出课表变更
+func printChanges(changes []schedule.Change) {
+	if len(changes) == 0 {
+		fmt.Printf("    %s 课表没有变化\n\n", green("✓"))
+		return
+	}
+	fmt.Printf("    %s 课表有 %s 处变化:\n", yellow("⚠"), bold(fmt.Sprintf("%d", len(changes))))
+	for _, c := range changes {
+		mark := yellow("~")
+		switch c.Kind {
+		case schedule.Added:
+			mark = green("+")
+		case schedule.Removed:
+			mark = magenta("-")
+		}
+		fmt.Printf("      %s %s\n", mark, c)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func runException(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: bistu-wakeup exception add|list|remove [参数]")
+	}
+
+	fs := newFlagSet("exception")
+	student := fs.String("student", "", "学号（默认为最近一次使用的学号）")
+	term := fs.String("term", "", "学期代码（默认为最近一次使用的学期）")
+	course := fs.String("course", "", "add: 课程名")
+	date := fs.String("date", "", "add: 原上课日期，补课时为补课日期 (YYYY-MM-DD)")
+	section := fs.Int("section", 0, "add: 原上课所在的节次（任一节即可），同一天有两次该课程时用于区分")
+	cancel := fs.Bool("cancel", false, "add: 停课")
+	extra := fs.Bool("extra", false, "add: 补课或加课")
+	toDate := fs.String("to", "", "add: 调课后的日期 (YYYY-MM-DD)")
+	sections := fs.String("sections", "", "add: 调课或补课的节次，如 3-4")
+	location := fs.String("location", "", "add: 调课或补课的地点")
+	note := fs.String("note", "", "add: 备注")
+	force := fs.Bool("force", false, "add: 原日期没有该课程时仍然添加")
+	sub := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	store, err := cache.Open("")
+	if err != nil {
+		return err
+	}
+	last, err := store.Latest()
+	if err != nil && (*student == "" || *term == "") {
+		return fmt.Errorf("请通过 --student 和 --term 指定学期: %w", err)
+	}
+	studentID, termCode := *student, *term
+	if studentID == "" {
+		studentID = last.StudentID
+	}
+	if termCode == "" {
+		termCode = last.Term
+	}
+	list, err := store.Exceptions(studentID, termCode)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		if len(list) == 0 {
+			fmt.Printf("    %s 没有调课记录\n", dim("·"))
+			return nil
+		}
+		fmt.Printf("%s %s\n", bold(studentID), schedule.FormatTermLabel(termCode, false))
+		for _, e := range list {
+			fmt.Printf("  %s\n", e)
+		}
+		return nil
+
+	case "add":
+		e := schedule.Exception{Kind: schedule.Move, Course: *course, Date: *date, Section: *section,
+			ToDate: *toDate, Location: *location, Note: *note}
+		switch {
+		case *cancel && *extra:
+			return fmt.Errorf("--cancel 与 --extra 不能同时使用")
+		case *cancel:
+			e.Kind = schedule.Cancel
+		case *extra:
+			e.Kind = schedule.Extra
+		}
+		if *sections != "" {
+			if e.ToBegin, e.ToEnd, err = schedule.ParseSections(*sections); err != nil {
+				return err
+			}
+		}
+		if err := e.Validate(); err != nil {
+			return err
+		}
+		if e.Kind != schedule.Extra && !*force {
+			if err := checkSessionExists(store, studentID, termCode, e); err != nil {
+				return err
+			}
+		}
+		for _, old := range list {
+			if old.ID >= e.ID {
+				e.ID = old.ID + 1
+			}
+		}
+		if e.ID == 0 {
+			e.ID = 1
+		}
+		list = append(list, e)
+		if err := store.SaveExceptions(studentID, termCode, list); err != nil {
+			return err
+		}
+		fmt.Printf("    %s 已添加 %s\n", green("✓"), e)
+		return nil
+
+	case "remove", "rm":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("请指定要删除的记录编号，可通过 exception list 查看")
+		}
+		for _, arg := range fs.Args() {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("无效的编号 %q", arg)
+			}
+			found := false
+			for i, e := range list {
+				if e.ID == id {
+					list = append(list[:i], list[i+1:]...)
+					fmt.Printf("    %s 已删除 %s\n", green("✓"), e)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("找不到编号为 %d 的记录", id)
+			}
+		}
+		return store.SaveExceptions(studentID, termCode, list)
+
+	default:
+		return fmt.Errorf("未知的子命令 %q，可用: add、list、remove", sub)
+	}
+}
+
+// checkSessionExists 确认停课或调课的那天确实有这门课，避免课程名或日期写错后静默失效
+func checkSessionExists(store *cache.Store, studentID, term string, e schedule.Exception) error {
+	entry, err := store.Load(studentID, term)
+	if err != nil {
+		return err
+	}
+	t, err := withOverrides(entryTimetable(entry))
+	if err != nil {
+		return err
+	}
+	if err := prepare(t, true); err != nil {
+		return err
+	}
+	if err := resolveStart(t, ""); err != nil {
+		return err
+	}
+	day, _ := schedule.ParseDate(e.Date)
+	var names []string
+	for _, s := range schedule.OnDate(t.Sessions(), day) {
+		if s.Course.Name == e.Course && s.Covers(e.Section) {
+			return nil
+		}
+		names = append(names, fmt.Sprintf("%s(%d-%d节)", s.Course.Name, s.BeginSection, s.EndSection))
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%s 没有课程，如确认无误请加 --force", e.Date)
+	}
+	return fmt.Errorf("%s 没有 %s，当天的课程: %s；如确认无误请加 --force", e.Date, e.Course, strings.Join(names, "、"))
+}
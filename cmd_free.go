@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// freeReport JSON 输出
+type freeReport struct {
+	Weeks []int          `json:"weeks"`
+	Slots []freeSlotJSON `json:"slots"`
+}
+
+type freeSlotJSON struct {
+	schedule.FreeSlot
+	Start   string `json:"startTime"`
+	End     string `json:"endTime"`
+	Minutes int    `json:"minutes"`
+}
+
+func runFree(args []string) error {
+	fs := newFlagSet("free")
+	week := fs.Int("week", 0, "只看第几周（默认整个学期每周都空闲的时间）")
+	weeksStr := fs.String("weeks", "", "只看这些周，如 1-8 或 3,5,7")
+	weekdays := fs.Bool("weekdays", false, "只看周一到周五")
+	daysStr := fs.String("days", "", "只看这些星期几，如 1-3,5")
+	after := fs.Int("after", 0, "只看第 N 节之后（如 4 表示从第 5 节开始）")
+	before := fs.Int("before", 0, "只看第 N 节之前")
+	min := fs.Int("min", 1, "至少连续空闲几节")
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("需要指定至少一份课表（文件路径或 %s）", cacheSource)
+	}
+	filter := schedule.FreeFilter{After: *after, Before: *before, MinSections: *min}
+	switch {
+	case *daysStr != "":
+		days, err := schedule.ParseWeeks(*daysStr)
+		if err != nil {
+			fs.Usage()
+			return fmt.Errorf("无效的星期: %w", err)
+		}
+		filter.Days = days
+	case *weekdays:
+		filter.Days = []int{1, 2, 3, 4, 5}
+	}
+	if err := filter.Validate(); err != nil {
+		fs.Usage()
+		return err
+	}
+
+	var timetables [][]schedule.Course
+	maxWeek := 0
+	for _, spec := range fs.Args() {
+		t, err := loadSource(spec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", spec, err)
+		}
+		timetables = append(timetables, t.Courses)
+		if w := schedule.MaxWeek(t.Courses); w > maxWeek {
+			maxWeek = w
+		}
+	}
+
+	var weeks []int
+	var err error
+	switch {
+	case *week > 0:
+		weeks = []int{*week}
+	case *weeksStr != "":
+		if weeks, err = schedule.ParseWeeks(*weeksStr); err != nil {
+			return err
+		}
+	default:
+		for w := 1; w <= maxWeek; w++ {
+			weeks = append(weeks, w)
+		}
+	}
+
+	slots := schedule.FindFreeSlots(timetables, weeks, filter)
+	if *asJSON {
+		report := freeReport{Weeks: weeks, Slots: make([]freeSlotJSON, 0, len(slots))}
+		for _, s := range slots {
+			report.Slots = append(report.Slots, freeSlotJSON{
+				FreeSlot: s,
+				Start:    schedule.SectionTimes[s.Begin-1].Begin,
+				End:      schedule.SectionTimes[s.End-1].End,
+				Minutes:  s.Minutes(),
+			})
+		}
+		return printJSON(report)
+	}
+
+	scope := "整个学期每周"
+	if len(weeks) == 1 {
+		scope = fmt.Sprintf("第 %d 周", weeks[0])
+	} else if *weeksStr != "" {
+		scope = fmt.Sprintf("第%s周每周", schedule.FormatWeeks(weeks))
+	}
+	fmt.Printf("\n  %s\n\n", bold(fmt.Sprintf("%d 份课表%s共同的空闲时间", len(timetables), scope)))
+	if len(slots) == 0 {
+		fmt.Printf("    %s 没有符合条件的共同空闲时间\n\n", yellow("⚠"))
+		return nil
+	}
+	for _, s := range slots {
+		fmt.Printf("    %s %s  %s-%s  %s\n", schedule.WeekdayName(s.Day),
+			dim(pad(fmt.Sprintf("%d-%d节", s.Begin, s.End), 7)),
+			schedule.SectionTimes[s.Begin-1].Begin, schedule.SectionTimes[s.End-1].End,
+			green(formatMinutes(s.Minutes())))
+	}
+	fmt.Println()
+	return nil
+}
+
+// formatMinutes 将分钟数格式化为 "2小时15分钟"
+func formatMinutes(m int) string {
+	switch {
+	case m < 60:
+		return fmt.Sprintf("%d分钟", m)
+	case m%60 == 0:
+		return fmt.Sprintf("%d小时", m/60)
+	default:
+		return fmt.Sprintf("%d小时%d分钟", m/60, m%60)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
+	"github.com/bistu-wakeup/bistu-wakeup/export"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func runHistory(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: bistu-wakeup history list|show|diff [参数]")
+	}
+
+	fs := newFlagSet("history")
+	student := fs.String("student", "", "学号（默认为最近一次使用的学号）")
+	term := fs.String("term", "", "学期代码（默认为最近一次使用的学期）")
+	at := fs.String("at", "", "show: 显示该日期 (YYYY-MM-DD) 当天生效的版本")
+	format := fs.String("format", "", "show: 以指定导出格式输出到标准输出，如 csv / ics / json")
+	asJSON := fs.Bool("json", false, "diff: 以 JSON 输出变更列表")
+	sub := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	store, err := cache.Open("")
+	if err != nil {
+		return err
+	}
+	studentID, termCode := *student, *term
+	if studentID == "" || termCode == "" {
+		last, err := store.Latest()
+		if err != nil {
+			return fmt.Errorf("请通过 --student 和 --term 指定学期: %w", err)
+		}
+		if studentID == "" {
+			studentID = last.StudentID
+		}
+		if termCode == "" {
+			termCode = last.Term
+		}
+	}
+	versions, err := store.History(studentID, termCode)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("%s 没有历史版本", schedule.FormatTermLabel(termCode, false))
+	}
+
+	switch sub {
+	case "list":
+		fmt.Printf("%s %s\n", bold(studentID), schedule.FormatTermLabel(termCode, false))
+		for _, v := range versions {
+			fmt.Printf("  %s  %s  %s  %d 门课程\n", cyan(fmt.Sprintf("#%-3d", v.Seq)),
+				dim(v.ShortHash()), v.FetchedAt.In(schedule.TZ).Format("2006-01-02 15:04"), len(v.Courses))
+		}
+		return nil
+
+	case "show":
+		var v *cache.Version
+		switch {
+		case *at != "":
+			day, err := schedule.ParseDate(*at)
+			if err != nil {
+				return err
+			}
+			v, err = cache.VersionAt(versions, day.Add(24*time.Hour-time.Second))
+			if err != nil {
+				return err
+			}
+		case fs.NArg() == 1:
+			if v, err = cache.FindVersion(versions, fs.Arg(0)); err != nil {
+				return err
+			}
+		default:
+			v = versions[len(versions)-1]
+		}
+		if *format == "" {
+			fmt.Printf("%s  %s  %s\n", cyan(fmt.Sprintf("#%d", v.Seq)), dim(v.ShortHash()),
+				v.FetchedAt.In(schedule.TZ).Format("2006-01-02 15:04"))
+			printCourses(v.Courses)
+			return nil
+		}
+		return writeVersion(store, studentID, termCode, v, *format)
+
+	case "diff":
+		if fs.NArg() != 2 {
+			return fmt.Errorf("用法: bistu-wakeup history diff <版本> <版本>")
+		}
+		a, err := cache.FindVersion(versions, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		b, err := cache.FindVersion(versions, fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		changes := schedule.Diff(a.Courses, b.Courses)
+		if *asJSON {
+			return printJSON(changes)
+		}
+		fmt.Printf("#%d → #%d\n", a.Seq, b.Seq)
+		printChanges(changes)
+		return nil
+
+	default:
+		return fmt.Errorf("未知的 history 子命令 %q（可选: list / show / diff）", sub)
+	}
+}
+
+// writeVersion 将历史版本按指定格式写到标准输出
+func writeVersion(store *cache.Store, studentID, term string, v *cache.Version, format string) error {
+	e, ok := export.Get(format)
+	if !ok {
+		return fmt.Errorf("不支持的导出格式 %q", format)
+	}
+	t := &export.Timetable{Term: term, StudentID: studentID, Courses: v.Courses}
+	if entry, err := store.Load(studentID, term); err == nil {
+		t.StartDate = entry.StartDate
+	}
+	// 与当前版本经过相同的整理：覆盖规则、调课记录、节假日、地点表和相邻节次合并
+	var err error
+	if t.Exceptions, err = store.Exceptions(studentID, term); err != nil {
+		return err
+	}
+	if t, err = withOverrides(t); err != nil {
+		return err
+	}
+	if err := prepare(t, true); err != nil {
+		return err
+	}
+	if err := resolveStart(t, ""); err != nil {
+		return err
+	}
+	return e.Write(os.Stdout, t)
+}
+
+// printCourses 按星期、节次输出课程列表
+func printCourses(courses []schedule.Course) {
+	sorted := append([]schedule.Course(nil), courses...)
+	sortCourses(sorted)
+	for _, c := range sorted {
+		when := c.DayOfWeek
+		if day, err := strconv.Atoi(c.DayOfWeek); err == nil {
+			when = schedule.WeekdayName(day)
+		}
+		fmt.Printf("  %s %s  %s  %s  %s  %s\n", when, dim(c.BeginSection+"-"+c.EndSection+"节"),
+			bold(c.Name), c.Location, c.Teacher, dim("第"+c.Weeks+"周"))
+	}
+}
+
+// sortCourses 按星期、开始节次、课程名排序
+func sortCourses(courses []schedule.Course) {
+	num := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	sort.SliceStable(courses, func(i, j int) bool {
+		a, b := courses[i], courses[j]
+		if num(a.DayOfWeek) != num(b.DayOfWeek) {
+			return num(a.DayOfWeek) < num(b.DayOfWeek)
+		}
+		if num(a.BeginSection) != num(b.BeginSection) {
+			return num(a.BeginSection) < num(b.BeginSection)
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
+	"github.com/bistu-wakeup/bistu-wakeup/export"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// contentTypes 各导出格式的 Content-Type
+var contentTypes = map[string]string{
+	"csv":  "text/csv; charset=utf-8",
+	"ics":  "text/calendar; charset=utf-8",
+	"json": "application/json; charset=utf-8",
+	"html": "text/html; charset=utf-8",
+}
+
+func contentType(ext string) string {
+	if ct, ok := contentTypes[ext]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// feed 提供订阅的课表，后台定时刷新，刷新失败时继续使用上一次的数据
+type feed struct {
+	sess      *session
+	term      string
+	start     time.Time // --start 指定的开学日期，优先于缓存中记住的日期
+	overrides string    // --overrides 指定的规则文件，为空时使用配置目录下的默认文件
+
+	mu    sync.RWMutex
+	entry *cache.Entry
+}
+
+func (f *feed) current() *cache.Entry {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.entry
+}
+
+func (f *feed) refresh() {
+	entry, previous, err := f.sess.fetch(f.term)
+	if err != nil {
+		logf("%s 刷新失败，继续使用缓存: %v", yellow("⚠"), err)
+		return
+	}
+	if previous != nil {
+		if changes := schedule.Diff(previous.Courses, entry.Courses); len(changes) > 0 {
+			logf("%s 课表有 %d 处变化", yellow("⚠"), len(changes))
+		}
+	}
+	logf("%s 已刷新，共 %d 门课程", green("✓"), len(entry.Courses))
+	f.mu.Lock()
+	f.entry = entry
+	f.mu.Unlock()
+}
+
+func (f *feed) loop(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+			f.refresh()
+		}
+	}
+}
+
+// handler 处理 /<token>/<file>.<ext> 请求
+func (f *feed) handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{token}/{file}", func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.PathValue("token")), []byte(token)) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+		base, ext, _ := strings.Cut(r.PathValue("file"), ".")
+		e, ok := export.Get(ext)
+		if !ok || (base != "calendar" && base != "schedule") {
+			http.NotFound(w, r)
+			return
+		}
+
+		entry := f.current()
+		if entry == nil {
+			http.Error(w, "课表尚未获取，请稍后再试", http.StatusServiceUnavailable)
+			return
+		}
+		// 每次请求重新读取，修改覆盖规则、新增调课、修改节假日和地点表都无需重启服务
+		rules, rulesPath, err := loadOverrides(f.overrides)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		t := entryTimetable(entry)
+		t.Courses = rules.Apply(t.Courses)
+		if err := prepare(t, true); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		t.Exceptions, _ = f.sess.store.Exceptions(entry.StudentID, entry.Term)
+		if !f.start.IsZero() {
+			t.StartDate = f.start
+		} else if t.StartDate.IsZero() {
+			t.StartDate, _ = schedule.GuessTermStart(t.Term)
+		}
+
+		var buf bytes.Buffer
+		if err := e.Write(&buf, t); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType(e.Ext()))
+		w.Header().Set("Cache-Control", "no-cache")
+		http.ServeContent(w, r, base+"."+e.Ext(), f.modTime(entry, rulesPath), bytes.NewReader(buf.Bytes()))
+	})
+	return mux
+}
+
+// modTime 返回输出内容的最后修改时间：获取课表、调课记录、覆盖规则、节假日和地点表中最晚的一个
+// 只用获取时间的话，新增调课后订阅客户端会一直收到 304
+func (f *feed) modTime(entry *cache.Entry, rulesPath string) time.Time {
+	latest := entry.FetchedAt
+	times := []time.Time{f.sess.store.ExceptionsModTime(entry.StudentID, entry.Term)}
+	paths := []string{rulesPath}
+	for _, name := range []string{"holidays.json", "locations.json"} {
+		if path, ok := configFile(name); ok {
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			times = append(times, info.ModTime())
+		}
+	}
+	for _, t := range times {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	addr := fs.String("addr", ":8080", "监听地址")
+	token := fs.String("token", "", "URL 中的访问令牌（默认自动生成并保存）")
+	term := fs.String("term", "", "学期代码（默认为最近一次使用的学期或当前学期）")
+	cookie := fs.String("cookie", "", "使用 Cookie 登录")
+	start := fs.String("start", "", "学期第一周周一的日期 (YYYY-MM-DD)")
+	interval := fs.Duration("interval", time.Hour, "刷新间隔")
+	overridesPath := fs.String("overrides", "", "覆盖规则文件 (YAML)，默认读取配置目录下的 overrides.yaml")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval < time.Minute {
+		return fmt.Errorf("刷新间隔不能小于 1 分钟")
+	}
+
+	store, err := cache.Open("")
+	if err != nil {
+		return err
+	}
+	if *token == "" {
+		if *token, err = loadOrCreateToken(filepath.Join(store.Dir, "serve_token")); err != nil {
+			return err
+		}
+	}
+	sess, err := newSession(*cookie, store)
+	if err != nil {
+		return err
+	}
+
+	f := &feed{sess: sess, term: *term, overrides: *overridesPath}
+	if f.term == "" {
+		f.term = defaultTerm(store)
+	}
+	if *start != "" {
+		if f.start, err = schedule.ParseDate(*start); err != nil {
+			return err
+		}
+	}
+	// 覆盖规则、节假日和地点表在每次请求时读取，这里先检查一遍格式
+	if _, _, err := loadOverrides(f.overrides); err != nil {
+		return err
+	}
+	if _, err := loadCalendar(); err != nil {
+		return err
+	}
+	if _, err := loadLocationTable(); err != nil {
+		return err
+	}
+
+	// 先登录确定学号，用该学号的缓存提供服务，再获取最新课表
+	// 最近一次缓存可能属于其他账号，不能直接使用
+	if err := sess.login(); err != nil {
+		logf("%s 登录失败，%s 后重试: %v", yellow("⚠"), *interval, err)
+	} else {
+		if cached, err := store.Load(sess.user.StudentID, f.term); err == nil {
+			f.entry = cached
+		}
+		f.refresh()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go f.loop(ctx, *interval)
+
+	srv := &http.Server{Addr: *addr, Handler: f.handler(*token), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	host := *addr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+	logf("订阅地址: %s", bold(fmt.Sprintf("http://%s/%s/calendar.ics", host, *token)))
+	logf("同时提供 schedule.json / schedule.csv / schedule.html，每 %s 刷新一次", *interval)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logf("服务已停止")
+	return nil
+}
+
+// loadOrCreateToken 读取已保存的令牌，没有则生成新的随机令牌
+// 令牌固定下来，日历应用订阅一次后重启服务也无需重新订阅
+func loadOrCreateToken(path string) (string, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		if t := strings.TrimSpace(string(data)); t != "" {
+			return t, nil
+		}
+	}
+	token, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("保存令牌失败: %w", err)
+	}
+	return token, nil
+}
+
+// randomToken 生成 n 字节随机数的十六进制字符串
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("生成随机令牌失败: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func runStats(args []string) error {
+	fs := newFlagSet("stats")
+	from := fs.String("from", cacheSource, "课表来源：@cache 或导出的 CSV / JSON、原始数据文件")
+	asJSON := fs.Bool("json", false, "以 JSON 输出统计结果")
+	top := fs.Int("top", 0, "课程和教师只列出学时最多的前 N 项（0 表示全部）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := loadSource(*from)
+	if err != nil {
+		return err
+	}
+	s := schedule.ComputeStats(t.Courses, t.Locations)
+	if *asJSON {
+		return printJSON(s)
+	}
+	if s.Sessions == 0 {
+		fmt.Printf("    %s 课表中没有可统计的课程\n\n", yellow("⚠"))
+		return nil
+	}
+
+	fmt.Printf("\n  %s\n\n", bold(schedule.FormatTermLabel(t.Term, false)+" 课表统计"))
+	fmt.Printf("    %d 门课程 · %d 次课 · %d 学时 · %.1f 小时\n", s.Courses, s.Sessions, s.Sections, float64(s.Minutes)/60)
+	if s.FirstClass != nil {
+		fmt.Printf("    最早 %s（%s %s）· 最晚 %s（%s %s）\n",
+			s.FirstClass.Time, schedule.WeekdayName(s.FirstClass.Day), s.FirstClass.Course,
+			s.LastClass.Time, schedule.WeekdayName(s.LastClass.Day), s.LastClass.Course)
+	}
+	fmt.Printf("    晚课 %d 次，占 %.0f%%\n", s.EveningSessions, s.EveningShare*100)
+	fmt.Printf("    最忙 %s · 最空 %s\n\n", dayNames(s.Busiest), dayNames(s.Emptiest))
+
+	printSection("每周学时")
+	maxWeek := 0
+	for _, w := range s.Weeks {
+		if w.Sections > maxWeek {
+			maxWeek = w.Sections
+		}
+	}
+	for _, w := range s.Weeks {
+		fmt.Printf("    %s %s %s\n", dim(pad(fmt.Sprintf("第%d周", w.Week), 7)), bar(w.Sections, maxWeek, 30), fmt.Sprint(w.Sections))
+	}
+	fmt.Println()
+
+	printSection("星期分布")
+	maxDay := 0
+	for _, d := range s.ByWeekday {
+		if d.Sections > maxDay {
+			maxDay = d.Sections
+		}
+	}
+	for _, d := range s.ByWeekday {
+		fmt.Printf("    %s %s %d\n", dim(pad(schedule.WeekdayName(d.Day), 7)), bar(d.Sections, maxDay, 30), d.Sections)
+	}
+	fmt.Println()
+
+	printLoads("课程", s.ByCourse, *top)
+	printLoads("教师", s.ByTeacher, *top)
+	// 只有一个校区时不必单独列出
+	if len(s.ByCampus) > 1 {
+		printLoads("校区", s.ByCampus, 0)
+	}
+	return nil
+}
+
+func printSection(title string) {
+	fmt.Printf("  %s\n", cyan(title))
+}
+
+// printLoads 输出 名称 / 次数 / 学时 / 小时 表格
+func printLoads(title string, loads []schedule.Load, top int) {
+	if top > 0 && len(loads) > top {
+		loads = loads[:top]
+	}
+	nameWidth := displayWidth(title)
+	for _, l := range loads {
+		if w := displayWidth(l.Name); w > nameWidth {
+			nameWidth = w
+		}
+	}
+	nameWidth = clamp(nameWidth, 4, 30)
+
+	printSection("按" + title)
+	fmt.Printf("    %s %s %s %s\n", dim(pad(title, nameWidth)), dim(padLeft("次数", 8)), dim(padLeft("学时", 8)), dim(padLeft("小时", 8)))
+	for _, l := range loads {
+		fmt.Printf("    %s %8d %8d %8.1f\n", pad(truncate(l.Name, nameWidth), nameWidth), l.Sessions, l.Sections, float64(l.Minutes)/60)
+	}
+	fmt.Println()
+}
+
+// bar 按比例绘制横向条形图
+func bar(n, max, width int) string {
+	if max == 0 {
+		return strings.Repeat(" ", width)
+	}
+	filled := n * width / max
+	return green(strings.Repeat("█", filled)) + strings.Repeat(" ", width-filled)
+}
+
+func dayNames(days []int) string {
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = schedule.WeekdayName(d)
+	}
+	return strings.Join(names, "、")
+}
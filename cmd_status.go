@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// defaultStatusTemplate 默认状态栏格式，如 "▶ 高等数学 3-101 · 12min"
+const defaultStatusTemplate = `{{if .Current}}▶ {{.Current.Short}} {{.Current.Location}} · {{.Current.Minutes}}min{{else if .Next}}○ {{.Next.Short}} {{.Next.Location}} · {{.Next.Minutes}}min{{end}}`
+
+// statusClass 状态栏模板中的一节课
+// Minutes 对正在上的课表示剩余分钟数，对下一节课表示距开始的分钟数
+type statusClass struct {
+	Name     string
+	Short    string
+	Location string
+	Teacher  string
+	Start    string
+	End      string
+	Minutes  int
+}
+
+// statusInfo 状态栏模板数据
+type statusInfo struct {
+	State    string // class 上课中 / break 课间 / free 今天已无课
+	Current  *statusClass
+	Next     *statusClass
+	Progress int // 当前课程进度百分比
+	Today    []statusClass
+}
+
+// waybarOutput waybar custom 模块的 JSON 格式（return-type: json）
+type waybarOutput struct {
+	Text       string `json:"text"`
+	Alt        string `json:"alt"`
+	Tooltip    string `json:"tooltip"`
+	Class      string `json:"class"`
+	Percentage int    `json:"percentage"`
+}
+
+func runStatus(args []string) error {
+	fs := newFlagSet("status")
+	from := fs.String("from", cacheSource, "课表来源：@cache 或导出的 CSV / JSON、原始数据文件")
+	start := fs.String("start", "", "学期第一周周一的日期 (YYYY-MM-DD)")
+	format := fs.String("format", defaultStatusTemplate, "输出模板（Go text/template），可用 .State .Current .Next .Progress")
+	empty := fs.String("empty", "", "当天没有后续课程时输出的文字")
+	short := fs.Int("short", 4, "课程简称保留的字数")
+	asJSON := fs.Bool("json", false, "输出 waybar custom 模块使用的 JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("status").Parse(*format)
+	if err != nil {
+		return fmt.Errorf("模板格式错误: %w", err)
+	}
+	sessions, err := loadSessions(*from, *start)
+	if err != nil {
+		return err
+	}
+
+	info := buildStatus(sessions, time.Now(), *short)
+	var text strings.Builder
+	if err := tmpl.Execute(&text, info); err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+	out := text.String()
+	if out == "" {
+		out = *empty
+	}
+
+	if !*asJSON {
+		fmt.Println(out)
+		return nil
+	}
+	var tooltip []string
+	for _, c := range info.Today {
+		tooltip = append(tooltip, fmt.Sprintf("%s-%s %s %s", c.Start, c.End, c.Name, c.Location))
+	}
+	return printCompactJSON(waybarOutput{
+		Text:       out,
+		Alt:        info.State,
+		Tooltip:    strings.Join(tooltip, "\n"),
+		Class:      info.State,
+		Percentage: info.Progress,
+	})
+}
+
+// buildStatus 根据当前时刻计算状态栏数据，只考虑当天的课程
+func buildStatus(sessions []schedule.Session, now time.Time, short int) statusInfo {
+	today := schedule.OnDate(sessions, now)
+	info := statusInfo{State: "free"}
+	for _, s := range today {
+		info.Today = append(info.Today, toStatusClass(s, 0, short))
+	}
+
+	if cur := schedule.Current(today, now); cur != nil {
+		info.State = "class"
+		left := cur.End.Sub(now)
+		c := toStatusClass(*cur, ceilMinutes(left), short)
+		info.Current = &c
+		total := cur.End.Sub(cur.Start)
+		info.Progress = int(100 * (total - left) / total)
+	}
+	if next := schedule.Next(today, now); next != nil {
+		if info.Current == nil {
+			info.State = "break"
+		}
+		c := toStatusClass(*next, ceilMinutes(next.Start.Sub(now)), short)
+		info.Next = &c
+	}
+	return info
+}
+
+func toStatusClass(s schedule.Session, minutes, short int) statusClass {
+	name := s.Course.Name
+	abbr := name
+	if r := []rune(name); short > 0 && len(r) > short {
+		abbr = string(r[:short])
+	}
+	return statusClass{
+		Name:     name,
+		Short:    abbr,
+		Location: s.Location,
+		Teacher:  s.Course.Teacher,
+		Start:    s.Start.Format("15:04"),
+		End:      s.End.Format("15:04"),
+		Minutes:  minutes,
+	}
+}
+
+// ceilMinutes 向上取整到分钟，避免还剩 30 秒时显示 0min
+func ceilMinutes(d time.Duration) int {
+	return int((d + time.Minute - 1) / time.Minute)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// loadSessions 读取课表并展开为逐次上课安排
+func loadSessions(from, start string) ([]schedule.Session, error) {
+	t, err := loadSource(from)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveStart(t, start); err != nil {
+		return nil, err
+	}
+	return t.Sessions(), nil
+}
+
+func runToday(args []string) error {
+	fs := newFlagSet("today")
+	from := fs.String("from", cacheSource, "课表来源：@cache 或导出的 CSV / JSON、原始数据文件")
+	start := fs.String("start", "", "学期第一周周一的日期 (YYYY-MM-DD)")
+	dateStr := fs.String("date", "", "查看指定日期 (YYYY-MM-DD)，默认今天")
+	tomorrow := fs.Bool("tomorrow", false, "查看明天")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sessions, err := loadSessions(*from, *start)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	date := now
+	switch {
+	case *dateStr != "":
+		if date, err = schedule.ParseDate(*dateStr); err != nil {
+			return err
+		}
+	case *tomorrow:
+		date = now.AddDate(0, 0, 1)
+	}
+
+	day := schedule.OnDate(sessions, date)
+	fmt.Printf("\n  %s %s\n\n", bold(date.In(schedule.TZ).Format("01-02")), schedule.WeekdayName(schedule.Weekday(date)))
+	if len(day) == 0 {
+		cal, _ := loadCalendar()
+		if h, ok := cal.HolidayOn(date); ok {
+			fmt.Printf("    %s\n\n", green(h.Name+"放假 🎉"))
+			return nil
+		}
+		fmt.Printf("    %s\n\n", green("没有课 🎉"))
+		return nil
+	}
+	for _, s := range day {
+		c := s.Course
+		line := fmt.Sprintf("%d-%d节  %s-%s  %s  %s  %s", s.BeginSection, s.EndSection,
+			s.Start.Format("15:04"), s.End.Format("15:04"), c.Name, "@"+s.Location, c.Teacher)
+		if s.Note != "" {
+			line += "  " + yellow(s.Note)
+		}
+		switch {
+		case !now.Before(s.End):
+			fmt.Printf("    %s %s\n", dim("✓"), dim(line))
+		case !now.Before(s.Start):
+			fmt.Printf("    %s %s  %s\n", green("▶"), bold(line), yellow("还剩 "+formatCountdown(s.End.Sub(now))))
+		default:
+			fmt.Printf("    %s %s\n", cyan("○"), line)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func runNext(args []string) error {
+	fs := newFlagSet("next")
+	from := fs.String("from", cacheSource, "课表来源：@cache 或导出的 CSV / JSON、原始数据文件")
+	start := fs.String("start", "", "学期第一周周一的日期 (YYYY-MM-DD)")
+	live := fs.Bool("live", false, "持续显示倒计时，Ctrl+C 退出")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sessions, err := loadSessions(*from, *start)
+	if err != nil {
+		return err
+	}
+	if !*live {
+		fmt.Println(nextLine(sessions, time.Now()))
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		fmt.Printf("\r\033[K%s", nextLine(sessions, time.Now()))
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// nextLine 描述正在上的课和下一节课
+func nextLine(sessions []schedule.Session, now time.Time) string {
+	line := ""
+	if cur := schedule.Current(sessions, now); cur != nil {
+		line = fmt.Sprintf("%s 正在上 %s @%s，还剩 %s  ", green("▶"), bold(cur.Course.Name),
+			cur.Location, formatCountdown(cur.End.Sub(now)))
+	}
+	next := schedule.Next(sessions, now)
+	if next == nil {
+		return line + dim("本学期没有后续课程了")
+	}
+	when := next.Start.Format("15:04")
+	if !sameDay(next.Start, now) {
+		when = next.Start.Format("01-02") + " " + schedule.WeekdayName(schedule.Weekday(next.Start)) + " " + when
+	}
+	return line + fmt.Sprintf("%s 下一节 %s @%s（%s，%s后）", cyan("○"), bold(next.Course.Name),
+		next.Location, when, formatCountdown(next.Start.Sub(now)))
+}
+
+// formatCountdown 倒计时描述：1 小时内精确到秒，1 天内精确到分钟
+func formatCountdown(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%d 分 %02d 秒", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%d 小时 %d 分钟", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%d 天 %d 小时", int(d.Hours()/24), int(d.Hours())%24)
+	}
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+	"golang.org/x/text/width"
+
+	"github.com/bistu-wakeup/bistu-wakeup/export"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// 课程色块，按课程名哈希选取，同一门课颜色固定
+var blockColors = []*color.Color{
+	color.New(color.BgCyan, color.FgBlack),
+	color.New(color.BgGreen, color.FgBlack),
+	color.New(color.BgYellow, color.FgBlack),
+	color.New(color.BgMagenta, color.FgWhite),
+	color.New(color.BgBlue, color.FgWhite),
+	color.New(color.BgRed, color.FgWhite),
+	color.New(color.BgHiCyan, color.FgBlack),
+	color.New(color.BgHiGreen, color.FgBlack),
+}
+
+var todayColor = color.New(color.FgYellow, color.Bold, color.Underline)
+
+// 网格布局参数
+const (
+	labelWidth   = 7  // 行首 "12 18:30"
+	minCellWidth = 6  // 小于该宽度时改为列表显示
+	maxCellWidth = 16 // 列宽上限
+)
+
+func runView(args []string) error {
+	fs := newFlagSet("view")
+	from := fs.String("from", cacheSource, "课表来源：@cache 或导出的 CSV / JSON、原始数据文件")
+	week := fs.Int("week", 0, "显示第几周（默认本周）")
+	start := fs.String("start", "", "学期第一周周一的日期 (YYYY-MM-DD)")
+	static := fs.Bool("static", false, "只打印一次，不进入键盘翻页模式")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := loadSource(*from)
+	if err != nil {
+		return err
+	}
+	if err := resolveStart(t, *start); err != nil {
+		return err
+	}
+
+	v := &weekView{t: t, now: time.Now()}
+	v.sessions = t.Sessions()
+	v.current = schedule.WeekOf(t.StartDate, v.now)
+	v.maxWeek = maxWeek(t.Courses)
+	v.week = *week
+	if v.week == 0 {
+		v.week = clamp(v.current, 1, v.maxWeek)
+	}
+
+	interactive := !*static && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+	if !interactive {
+		fmt.Print(v.render(terminalWidth()))
+		return nil
+	}
+	return v.interact()
+}
+
+// resolveStart 确定开学日期：--start > 课表中记录的日期 > 按惯例推算
+func resolveStart(t *export.Timetable, start string) error {
+	if start != "" {
+		d, err := schedule.ParseDate(start)
+		if err != nil {
+			return err
+		}
+		t.StartDate = d
+		return nil
+	}
+	if !t.StartDate.IsZero() {
+		return nil
+	}
+	d, err := schedule.GuessTermStart(t.Term)
+	if err != nil {
+		return fmt.Errorf("无法确定开学日期，请通过 --start 指定")
+	}
+	t.StartDate = d
+	return nil
+}
+
+// weekView 按周显示的课表网格
+type weekView struct {
+	t        *export.Timetable
+	sessions []schedule.Session
+	now      time.Time
+	week     int
+	current  int
+	maxWeek  int
+}
+
+// interact 进入键盘翻页模式：←/→ 或 h/l 翻周，t 回到本周，q 退出
+func (v *weekView) interact() error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		fmt.Print(v.render(terminalWidth()))
+		return nil
+	}
+	defer term.Restore(fd, state)
+
+	buf := make([]byte, 8)
+	for {
+		// raw 模式下换行需要回车
+		screen := strings.ReplaceAll(v.render(terminalWidth()), "\n", "\r\n")
+		fmt.Print("\033[H\033[2J" + screen)
+		fmt.Print(dim("  ←/→ 翻周 · t 本周 · q 退出") + "\r\n")
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil
+		}
+		switch key := string(buf[:n]); key {
+		case "q", "Q", "\x1b", "\x03":
+			return nil
+		case "\x1b[D", "h", "p", "a":
+			v.week = clamp(v.week-1, 1, v.maxWeek)
+		case "\x1b[C", "l", "n", "d", " ":
+			v.week = clamp(v.week+1, 1, v.maxWeek)
+		case "t", "T":
+			v.week = clamp(v.current, 1, v.maxWeek)
+		}
+	}
+}
+
+// render 生成整屏内容，终端过窄时改为按天列出
+func (v *weekView) render(termWidth int) string {
+	var b strings.Builder
+	monday := v.t.StartDate.AddDate(0, 0, (v.week-1)*7)
+
+	title := fmt.Sprintf("第 %d 周", v.week)
+	if v.week == v.current {
+		title += "（本周）"
+	}
+	fmt.Fprintf(&b, "\n  %s  %s\n\n", bold(title),
+		dim(monday.Format("2006-01-02")+" ~ "+monday.AddDate(0, 0, 6).Format("01-02")))
+
+	days := 5
+	cells, lastSection := v.cells(monday)
+	if cells[5] != nil || cells[6] != nil {
+		days = 7
+	}
+
+	cellWidth := (termWidth - labelWidth - 2) / days
+	if cellWidth > maxCellWidth {
+		cellWidth = maxCellWidth
+	}
+	if cellWidth < minCellWidth {
+		v.renderList(&b, monday, days)
+		return b.String()
+	}
+
+	// 表头
+	b.WriteString(strings.Repeat(" ", labelWidth+2))
+	for d := 1; d <= days; d++ {
+		date := monday.AddDate(0, 0, d-1)
+		label := fmt.Sprintf("%s %s", schedule.WeekdayName(d), date.Format("1/2"))
+		if _, ok := v.t.Calendar.HolidayOn(date); ok {
+			label += " 休"
+		} else if _, ok := v.t.Calendar.WorkdayOn(date); ok {
+			label += " 班"
+		}
+		head := pad(truncate(label, cellWidth-1), cellWidth)
+		if sameDay(date, v.now) {
+			head = todayColor.Sprint(head)
+		}
+		b.WriteString(head)
+	}
+	b.WriteString("\n")
+
+	for sec := 1; sec <= lastSection; sec++ {
+		label := fmt.Sprintf("%2d %s", sec, schedule.SectionTimes[sec-1].Begin)
+		b.WriteString("  " + dim(pad(label, labelWidth)))
+		for d := 1; d <= days; d++ {
+			_, off := v.t.Calendar.HolidayOn(monday.AddDate(0, 0, d-1))
+			b.WriteString(v.cell(cells[d-1], sec, cellWidth, off))
+		}
+		b.WriteString("\n")
+	}
+	v.renderNotes(&b, monday)
+	return b.String()
+}
+
+// gridCell 网格中的一格：所属的一次课以及在其连续节次中的位置
+type gridCell struct {
+	session *schedule.Session
+	offset  int
+}
+
+// cells 返回本周每天每节的课程，以及需要显示到的最后一节
+func (v *weekView) cells(monday time.Time) ([7]map[int]gridCell, int) {
+	var cells [7]map[int]gridCell
+	last := 10
+	for _, s := range v.weekSessions(monday) {
+		day := schedule.Weekday(s.Start)
+		if cells[day-1] == nil {
+			cells[day-1] = make(map[int]gridCell)
+		}
+		for sec := s.BeginSection; sec <= s.EndSection; sec++ {
+			if _, taken := cells[day-1][sec]; !taken {
+				cells[day-1][sec] = gridCell{session: s, offset: sec - s.BeginSection}
+			}
+		}
+		if s.EndSection > last {
+			last = s.EndSection
+		}
+	}
+	if last > len(schedule.SectionTimes) {
+		last = len(schedule.SectionTimes)
+	}
+	return cells, last
+}
+
+// cell 渲染一格：第一行课程名，第二行地点，其余行留空色块
+func (v *weekView) cell(day map[int]gridCell, sec, w int, off bool) string {
+	gc, ok := day[sec]
+	if !ok {
+		if off {
+			return dim(pad(" 休", w))
+		}
+		return dim(pad(" ·", w))
+	}
+	text := ""
+	switch gc.offset {
+	case 0:
+		text = gc.session.Course.Name
+		if gc.session.Note != "" {
+			text = "*" + text
+		}
+	case 1:
+		text = "@" + gc.session.Location
+	}
+	// 右侧留一列空白分隔相邻课程
+	return colorFor(gc.session.Course.Name).Sprint(pad(" "+truncate(text, w-2), w-1)) + " "
+}
+
+// renderNotes 在网格下方列出本周的假期、调休以及调课标记说明
+func (v *weekView) renderNotes(b *strings.Builder, monday time.Time) {
+	end := monday.AddDate(0, 0, 7)
+	var notes []string
+	for _, h := range v.t.Calendar.HolidaysBetween(monday, end) {
+		notes = append(notes, fmt.Sprintf("%s 放假 %s ~ %s", h.Name, h.From[5:], h.To[5:]))
+	}
+	for _, w := range v.t.Calendar.WorkdaysBetween(monday, end) {
+		note := fmt.Sprintf("%s %s 上班", w.Name, w.Date[5:])
+		if follows, err := schedule.ParseDate(w.Follows); err == nil {
+			note = fmt.Sprintf("%s %s 按%s（%s）课表上课", w.Name, w.Date[5:],
+				schedule.WeekdayName(schedule.Weekday(follows)), w.Follows[5:])
+		}
+		notes = append(notes, note)
+	}
+	for _, s := range v.weekSessions(monday) {
+		if s.Note != "" {
+			notes = append(notes, "* 标记的课程本周有调课、补课或调休")
+			break
+		}
+	}
+	// 行首是默认作息时间，单独配置作息的校区另行说明
+	seen := make(map[string]bool)
+	for _, s := range v.weekSessions(monday) {
+		campus := schedule.ParseLocation(s.Location, v.t.Locations).Campus
+		times, ok := v.t.Locations.CampusTimes(campus)
+		if !ok || seen[campus] {
+			continue
+		}
+		seen[campus] = true
+		notes = append(notes, fmt.Sprintf("%s按本校区作息时间上课，第1节 %s 开始", campus, times[0].Begin))
+	}
+	if len(notes) > 0 {
+		b.WriteString("\n")
+	}
+	for _, n := range notes {
+		b.WriteString("  " + dim(n) + "\n")
+	}
+}
+
+// weekSessions 返回从 monday 开始这一周的上课安排（已应用调课）
+func (v *weekView) weekSessions(monday time.Time) []*schedule.Session {
+	end := monday.AddDate(0, 0, 7)
+	var out []*schedule.Session
+	for i := range v.sessions {
+		s := &v.sessions[i]
+		if !s.Start.Before(monday) && s.Start.Before(end) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// renderList 窄终端下按天列出本周课程
+func (v *weekView) renderList(b *strings.Builder, monday time.Time, days int) {
+	for d := 1; d <= days; d++ {
+		date := monday.AddDate(0, 0, d-1)
+		head := fmt.Sprintf("%s %s", schedule.WeekdayName(d), date.Format("1/2"))
+		if sameDay(date, v.now) {
+			head = todayColor.Sprint(head)
+		}
+		b.WriteString("  " + head + "\n")
+
+		found := false
+		for _, s := range v.weekSessions(monday) {
+			if !sameDay(s.Start, date) {
+				continue
+			}
+			found = true
+			fmt.Fprintf(b, "    %s %s %s %s", dim(fmt.Sprintf("%d-%d", s.BeginSection, s.EndSection)),
+				dim(s.Start.In(schedule.TZ).Format("15:04")+"-"+s.End.In(schedule.TZ).Format("15:04")),
+				colorFor(s.Course.Name).Sprint(" "+s.Course.Name+" "), s.Location)
+			if s.Note != "" {
+				b.WriteString(" " + yellow(s.Note))
+			}
+			b.WriteString("\n")
+		}
+		if !found {
+			b.WriteString(dim("    无课") + "\n")
+		}
+	}
+}
+
+func colorFor(name string) *color.Color {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return blockColors[h.Sum32()%uint32(len(blockColors))]
+}
+
+// maxWeek 返回课表中出现的最大周次，至少为 1
+func maxWeek(courses []schedule.Course) int {
+	if max := schedule.MaxWeek(courses); max > 1 {
+		return max
+	}
+	return 1
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.In(schedule.TZ).Date()
+	by, bm, bd := b.In(schedule.TZ).Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func clamp(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
+
+// terminalWidth 返回终端宽度，无法获取时参考 COLUMNS 环境变量，默认 100 列
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 100
+}
+
+// displayWidth 返回字符串在终端中的显示宽度（中文等宽字符计为 2）
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// truncate 按显示宽度截断，超出时以 … 结尾
+func truncate(s string, w int) string {
+	if w <= 0 {
+		return ""
+	}
+	if displayWidth(s) <= w {
+		return s
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		rw := runeWidth(r)
+		if n+rw > w-1 {
+			break
+		}
+		b.WriteRune(r)
+		n += rw
+	}
+	return b.String() + "…"
+}
+
+// pad 按显示宽度右侧补空格
+func pad(s string, w int) string {
+	if n := displayWidth(s); n < w {
+		return s + strings.Repeat(" ", w-n)
+	}
+	return s
+}
+
+// padLeft 按显示宽度左侧补空格（右对齐）
+func padLeft(s string, w int) string {
+	if n := displayWidth(s); n < w {
+		return strings.Repeat(" ", w-n) + s
+	}
+	return s
+}
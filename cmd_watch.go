@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
+	"github.com/bistu-wakeup/bistu-wakeup/notify"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// stringList 可重复指定的字符串参数
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func runWatch(args []string) error {
+	fs := newFlagSet("watch")
+	term := fs.String("term", "", "学期代码（默认为最近一次使用的学期或当前学期）")
+	cookie := fs.String("cookie", "", "使用 Cookie 登录")
+	interval := fs.Duration("interval", 30*time.Minute, "轮询间隔")
+	once := fs.Bool("once", false, "只检查一次后退出")
+	quiet := fs.Bool("quiet", false, "不在终端打印变更")
+	var webhooks, execs stringList
+	fs.Var(&webhooks, "webhook", "变更时 POST JSON 到该地址（可重复）")
+	fs.Var(&execs, "exec", "变更时执行的命令，事件 JSON 通过标准输入传入（可重复）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval < time.Minute {
+		return fmt.Errorf("轮询间隔不能小于 1 分钟")
+	}
+
+	var sinks []notify.Sink
+	if !*quiet {
+		sinks = append(sinks, notify.Stdout{})
+	}
+	for _, u := range webhooks {
+		sinks = append(sinks, notify.Webhook{URL: u})
+	}
+	for _, c := range execs {
+		sinks = append(sinks, notify.Exec{Command: c})
+	}
+
+	store, err := cache.Open("")
+	if err != nil {
+		return err
+	}
+	sess, err := newSession(*cookie, store)
+	if err != nil {
+		return err
+	}
+	termCode := *term
+	if termCode == "" {
+		termCode = defaultTerm(store)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logf("开始监控 %s，每 %s 检查一次", schedule.FormatTermLabel(termCode, false), *interval)
+	for {
+		checkOnce(ctx, sess, termCode, sinks)
+		if *once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			logf("已停止监控")
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// checkOnce 获取一次课表并与上次快照比较，有变化时发送通知
+func checkOnce(ctx context.Context, sess *session, term string, sinks []notify.Sink) {
+	entry, previous, err := sess.fetch(term)
+	if err != nil {
+		logf("%s 获取课表失败: %v", yellow("⚠"), err)
+		return
+	}
+	if previous == nil {
+		logf("%s 已记录 %d 门课程作为基准", green("✓"), len(entry.Courses))
+		return
+	}
+
+	changes := schedule.Diff(previous.Courses, entry.Courses)
+	if len(changes) == 0 {
+		logf("%s 课表没有变化", green("✓"))
+		return
+	}
+	event := notify.Event{
+		Time:      entry.FetchedAt,
+		StudentID: entry.StudentID,
+		Term:      entry.Term,
+		Changes:   changes,
+	}
+	if err := notify.Broadcast(ctx, sinks, event); err != nil {
+		logf("%s 发送通知失败: %v", yellow("⚠"), err)
+	}
+}
+
+// defaultTerm 默认学期：最近一次缓存的学期，没有缓存时按日期推算
+func defaultTerm(store *cache.Store) string {
+	if entry, err := store.Latest(); err == nil {
+		return entry.Term
+	}
+	return schedule.CurrentTerm(time.Now())
+}
+
+// logf 输出带时间戳的日志行
+func logf(format string, args ...interface{}) {
+	fmt.Printf("[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
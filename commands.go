@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command 子命令，如 bistu-wakeup diff a.csv b.csv
+// 不带子命令时执行默认的登录导出流程
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"diff", "diff [--json] <旧课表> <新课表>", "比较两个版本的课表，列出调课、新增和取消", runDiff},
+		{"watch", "watch [参数]", "定时检查课表，调课时通过终端、webhook 或命令通知", runWatch},
+		{"history", "history list|show|diff", "查看本学期课表的历史版本，或导出某一天的课表", runHistory},
+		{"serve", "serve [参数]", "启动 HTTP 服务，提供可订阅的 ICS 日历", runServe},
+		{"view", "view [--week N]", "在终端按周显示课表网格，可用方向键翻周", runView},
+		{"today", "today [--tomorrow]", "列出今天的课程（读取本地缓存，可离线使用）", runToday},
+		{"next", "next [--live]", "显示下一节课的时间、地点和倒计时", runNext},
+		{"exception", "exception add|list|remove", "记录停课、调课和补课，导出 ICS 和查看课表时生效", runException},
+		{"conflicts", "conflicts [--json]", "检查课表中的时间冲突，并指出对应的原始记录", runConflicts},
+		{"stats", "stats [--json]", "统计每周、每门课、每位教师和每天的学时", runStats},
+		{"free", "free [参数] <课表>...", "找出多份课表共同的空闲时间，按时长排序", runFree},
+		{"status", "status [--json]", "输出一行当前/下一节课信息，供 tmux、waybar、polybar 使用", runStatus},
+	}
+
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "用法:\n  bistu-wakeup [参数]            登录教务系统并导出课表\n")
+		for _, c := range commands {
+			fmt.Fprintf(out, "  bistu-wakeup %-18s %s\n", c.usage, c.summary)
+		}
+		fmt.Fprintf(out, "\n参数:\n")
+		flag.PrintDefaults()
+	}
+}
+
+// findCommand 按名称查找子命令
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// newFlagSet 创建子命令的参数解析器，出错时打印子命令用法
+func newFlagSet(c string) *flag.FlagSet {
+	fs := flag.NewFlagSet(c, flag.ContinueOnError)
+	fs.Usage = func() {
+		if cmd, ok := findCommand(c); ok {
+			fmt.Fprintf(fs.Output(), "用法: bistu-wakeup %s\n  %s\n\n", cmd.usage, cmd.summary)
+		}
+		fs.PrintDefaults()
+	}
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// printJSON 以缩进 JSON 输出到标准输出
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// printCompactJSON 以单行 JSON 输出到标准输出
+func printCompactJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
@@ -1,33 +1,41 @@
 package export
 
 import (
-	"fmt"
-	"os"
+	"bufio"
+	"io"
 	"strings"
 )
 
 var header = []string{"课程名称", "星期", "开始节数", "结束节数", "老师", "地点", "周数"}
 
-// WriteCSV 生成 WakeUp 格式的 CSV 文件
-func WriteCSV(filename string, courses [][]string) error {
-	f, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %w", err)
-	}
-	defer f.Close()
+func init() {
+	Register(csvExporter{})
+}
+
+// csvExporter 生成 WakeUp 格式的 CSV 文件
+type csvExporter struct{}
+
+func (csvExporter) Name() string { return "csv" }
+func (csvExporter) Ext() string  { return "csv" }
+
+func (csvExporter) Write(w io.Writer, t *Timetable) error {
+	bw := bufio.NewWriter(w)
 
 	// UTF-8 BOM
-	f.WriteString("\uFEFF")
+	bw.WriteString("\uFEFF")
 
 	// 表头
-	f.WriteString(formatRow(header) + "\n")
+	bw.WriteString(formatRow(header) + "\n")
 
 	// 数据行
-	for _, row := range courses {
-		f.WriteString(formatRow(row) + "\n")
+	for _, c := range t.Courses {
+		bw.WriteString(formatRow([]string{
+			c.Name, c.DayOfWeek, c.BeginSection,
+			c.EndSection, c.Teacher, c.Location, c.Weeks,
+		}) + "\n")
 	}
 
-	return nil
+	return bw.Flush()
 }
 
 // formatRow 将一行数据格式化为 CSV 行（双引号包裹，逗号分隔）
@@ -0,0 +1,192 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// ReadCSVFile 读取 WakeUp 格式的 CSV 文件
+func ReadCSVFile(filename string) ([]schedule.Course, []schedule.Rejected, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer f.Close()
+	return ReadCSV(f)
+}
+
+// ReadCSV 将 WakeUp 格式的 CSV 解析回课程列表
+// 兼容本工具导出的文件（UTF-8 BOM、双引号包裹）以及经 Excel 编辑另存的文件
+// （GBK 编码、去掉引号、分号或制表符分隔、周数被识别成日期等）
+// 星期或节次无法识别的行不会返回，而是与 schedule.ParseRecords 一样单独列出
+func ReadCSV(r io.Reader) ([]schedule.Course, []schedule.Rejected, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取 CSV 失败: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	if !utf8.Valid(data) {
+		// 中文 Excel "CSV（逗号分隔）" 默认以 GBK 保存
+		decoded, err := simplifiedchinese.GB18030.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("无法识别文件编码: %w", err)
+		}
+		data = decoded
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = sniffDelimiter(data)
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析 CSV 失败: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("CSV 文件为空")
+	}
+
+	cols, err := headerIndex(records[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	courses := make([]schedule.Course, 0, len(records)-1)
+	var rejected []schedule.Rejected
+	for i, rec := range records[1:] {
+		if isBlankRecord(rec) {
+			continue
+		}
+		get := func(col int) string {
+			idx := cols[col]
+			if idx < 0 || idx >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[idx])
+		}
+
+		c := schedule.Course{
+			Name:         get(0),
+			DayOfWeek:    normalizeNumber(get(1), schedule.NormalizeWeekday),
+			BeginSection: normalizeNumber(get(2), schedule.NormalizeSection),
+			EndSection:   normalizeNumber(get(3), schedule.NormalizeSection),
+			Teacher:      orNone(get(4)),
+			Location:     orNone(get(5)),
+			Weeks:        orNone(undoExcelDate(get(6))),
+		}
+		if c.Name == "" {
+			return nil, nil, fmt.Errorf("第 %d 行缺少课程名称", i+2)
+		}
+		if _, _, _, err := c.Slot(); err != nil {
+			rejected = append(rejected, schedule.Rejected{Line: i + 2, Name: c.Name, Reason: err.Error()})
+			continue
+		}
+		courses = append(courses, c)
+	}
+	return courses, rejected, nil
+}
+
+// headerAliases 各列可接受的表头名称，顺序与 header 一致
+var headerAliases = [][]string{
+	{"课程名称", "课程名", "课程"},
+	{"星期", "周几"},
+	{"开始节数", "开始节次", "开始节"},
+	{"结束节数", "结束节次", "结束节"},
+	{"老师", "教师", "任课教师"},
+	{"地点", "上课地点", "教室"},
+	{"周数", "上课周次", "周次"},
+}
+
+// headerIndex 按表头名称定位各列，允许列顺序被调整
+func headerIndex(row []string) ([]int, error) {
+	cols := make([]int, len(headerAliases))
+	for i := range cols {
+		cols[i] = -1
+	}
+	for idx, name := range row {
+		name = strings.TrimSpace(strings.Trim(name, `"`))
+		for col, aliases := range headerAliases {
+			for _, alias := range aliases {
+				if name == alias && cols[col] < 0 {
+					cols[col] = idx
+				}
+			}
+		}
+	}
+	for col, idx := range cols {
+		// 老师、地点可以缺省，其余列必须存在
+		if idx < 0 && col != 4 && col != 5 {
+			return nil, fmt.Errorf("CSV 缺少 %q 列，请确认是 WakeUp 格式", header[col])
+		}
+	}
+	return cols, nil
+}
+
+// sniffDelimiter 根据表头行判断分隔符
+func sniffDelimiter(data []byte) rune {
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	best, bestCount := ',', bytes.Count(first, []byte(","))
+	for _, d := range []rune{'\t', ';'} {
+		if n := bytes.Count(first, []byte(string(d))); n > bestCount {
+			best, bestCount = d, n
+		}
+	}
+	return best
+}
+
+func isBlankRecord(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeNumber 将 Excel 写出的 "3.0"、手工填写的 "周三" 等还原为整数字符串
+func normalizeNumber(s string, parse func(string) (int, error)) string {
+	if n, err := parse(s); err == nil {
+		return strconv.Itoa(n)
+	}
+	return orNone(s)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "无"
+	}
+	return s
+}
+
+var (
+	zhDateRe = regexp.MustCompile(`^(\d{1,2})月(\d{1,2})日$`)
+	enDateRe = regexp.MustCompile(`^(\d{1,2})-(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)$`)
+	months   = map[string]int{
+		"Jan": 1, "Feb": 2, "Mar": 3, "Apr": 4, "May": 5, "Jun": 6,
+		"Jul": 7, "Aug": 8, "Sep": 9, "Oct": 10, "Nov": 11, "Dec": 12,
+	}
+)
+
+// undoExcelDate 还原被 Excel 自动识别为日期的周数，如 "1月16日"、"16-Jan" → "1-16"
+func undoExcelDate(s string) string {
+	if m := zhDateRe.FindStringSubmatch(s); m != nil {
+		return m[1] + "-" + m[2]
+	}
+	if m := enDateRe.FindStringSubmatch(s); m != nil {
+		return strconv.Itoa(months[m[2]]) + "-" + m[1]
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func TestReadCSV(t *testing.T) {
+	want := []schedule.Course{{
+		Name: "高等数学", DayOfWeek: "3", BeginSection: "3", EndSection: "4",
+		Teacher: "张三", Location: "3-101", Weeks: "1-16",
+	}}
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"本工具导出", "\uFEFF\"课程名称\",\"星期\",\"开始节数\",\"结束节数\",\"老师\",\"地点\",\"周数\"\n" +
+			"\"高等数学\",\"3\",\"3\",\"4\",\"张三\",\"3-101\",\"1-16\"\n"},
+		{"Excel 去掉引号、数值带小数", "课程名称,星期,开始节数,结束节数,老师,地点,周数\n高等数学,3.0,3.0,4.0,张三,3-101,1-16\n"},
+		{"分号分隔", "课程名称;星期;开始节数;结束节数;老师;地点;周数\n高等数学;3;3;4;张三;3-101;1-16\n"},
+		{"制表符分隔", "课程名称\t星期\t开始节数\t结束节数\t老师\t地点\t周数\n高等数学\t3\t3\t4\t张三\t3-101\t1-16\n"},
+		{"周数被识别为日期", "课程名称,星期,开始节数,结束节数,老师,地点,周数\n高等数学,3,3,4,张三,3-101,1月16日\n"},
+		{"英文日期", "课程名称,星期,开始节数,结束节数,老师,地点,周数\n高等数学,3,3,4,张三,3-101,16-Jan\n"},
+		{"列顺序调整、表头别名", "周次,课程,周几,开始节次,结束节次,教室,任课教师\n1-16,高等数学,周三,第3节,第四节,3-101,张三\n"},
+		{"空行", "课程名称,星期,开始节数,结束节数,老师,地点,周数\n\n高等数学,3,3,4,张三,3-101,1-16\n,,,,,,\n"},
+		{"CRLF 换行", "课程名称,星期,开始节数,结束节数,老师,地点,周数\r\n高等数学,3,3,4,张三,3-101,1-16\r\n"},
+	}
+	for _, tt := range tests {
+		got, rejected, err := ReadCSV(strings.NewReader(tt.in))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(rejected) > 0 {
+			t.Errorf("%s: rejected %+v", tt.name, rejected)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\n got  %+v\n want %+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestReadCSVGBK(t *testing.T) {
+	utf8 := "课程名称,星期,开始节数,结束节数,老师,地点,周数\n高等数学,3,3,4,张三,3-101,1-16\n"
+	gbk, err := simplifiedchinese.GB18030.NewEncoder().Bytes([]byte(utf8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := ReadCSV(bytes.NewReader(gbk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "高等数学" || got[0].Teacher != "张三" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestReadCSVOptionalColumns(t *testing.T) {
+	got, _, err := ReadCSV(strings.NewReader("课程名称,星期,开始节数,结束节数,周数\n高等数学,3,3,4,1-16\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Teacher != "无" || got[0].Location != "无" {
+		t.Errorf("Teacher=%q Location=%q, want 无", got[0].Teacher, got[0].Location)
+	}
+}
+
+func TestReadCSVRejected(t *testing.T) {
+	in := "课程名称,星期,开始节数,结束节数,老师,地点,周数\n" +
+		"高等数学,3,3,4,张三,3-101,1-16\n" +
+		"体育,星期八,1,2,赵六,操场,1-16\n" +
+		"化学,2,1.5,2,钱七,2-101,1-16\n" +
+		"物理,2,4,3,王五,2-102,1-16\n" +
+		"英语,,1,2,李四,1-101,1-16\n"
+	got, rejected, err := ReadCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "高等数学" {
+		t.Errorf("courses = %+v, want only 高等数学", got)
+	}
+	var lines []int
+	for _, r := range rejected {
+		lines = append(lines, r.Line)
+	}
+	if want := []int{3, 4, 5, 6}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("rejected lines = %v, want %v (%+v)", lines, want, rejected)
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"空文件", ""},
+		{"缺少星期列", "课程名称,开始节数,结束节数,老师,地点,周数\n高等数学,3,4,张三,3-101,1-16\n"},
+		{"缺少课程名称", "课程名称,星期,开始节数,结束节数,老师,地点,周数\n,3,3,4,张三,3-101,1-16\n"},
+	}
+	for _, tt := range tests {
+		if got, _, err := ReadCSV(strings.NewReader(tt.in)); err == nil {
+			t.Errorf("%s: got %+v, want error", tt.name, got)
+		}
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	courses := []schedule.Course{
+		{Name: "高等数学", DayOfWeek: "3", BeginSection: "3", EndSection: "4", Teacher: "张三,李四", Location: "3-101", Weeks: "1-8,10-16"},
+		{Name: "体育（篮球）", DayOfWeek: "5", BeginSection: "7", EndSection: "8", Teacher: "无", Location: "操场", Weeks: "1-15单"},
+	}
+	var buf bytes.Buffer
+	if err := (csvExporter{}).Write(&buf, &Timetable{Courses: courses}); err != nil {
+		t.Fatal(err)
+	}
+	got, rejected, err := ReadCSV(&buf)
+	if err != nil || len(rejected) > 0 {
+		t.Fatalf("err=%v rejected=%+v", err, rejected)
+	}
+	if !reflect.DeepEqual(got, courses) {
+		t.Errorf("round trip:\n got  %+v\n want %+v", got, courses)
+	}
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// Timetable 导出所需的完整课表数据
+type Timetable struct {
+	Term      string            `json:"term"`
+	StudentID string            `json:"studentId,omitempty"`
+	StartDate time.Time         `json:"startDate"`
+	Courses   []schedule.Course `json:"courses"`
+
+	// Unscheduled 未安排时间的课程，无法放入课表格子
+	Unscheduled []schedule.Unscheduled `json:"unscheduled,omitempty"`
+	// Rejected 星期或节次无法识别而未写入课表的原始记录，仅用于提示
+	Rejected []schedule.Rejected `json:"-"`
+	// Exceptions 停课、调课和补课，展开为具体日期时生效（ICS 等）
+	Exceptions []schedule.Exception `json:"exceptions,omitempty"`
+	// Calendar 节假日和调休安排，为空时不调整
+	Calendar *schedule.Calendar `json:"-"`
+	// Locations 地点别名表，为空时使用默认表
+	Locations *schedule.LocationTable `json:"-"`
+}
+
+// Sessions 将课表展开为逐次上课安排，已按节假日、调休、单次调整和校区作息时间修正
+func (t *Timetable) Sessions() []schedule.Session {
+	return schedule.Expand(t.Courses, t.StartDate, schedule.Adjustments{
+		Calendar:   t.Calendar,
+		Exceptions: t.Exceptions,
+		Locations:  t.Locations,
+	})
+}
+
+// Exporter 导出格式
+// 新格式只需实现该接口并在 init 中调用 Register，无需修改 main.go
+type Exporter interface {
+	// Name 格式名称，对应 --format 参数
+	Name() string
+	// Ext 文件扩展名（不含点）
+	Ext() string
+	// Write 将课表写入 w
+	Write(w io.Writer, t *Timetable) error
+}
+
+var registry = make(map[string]Exporter)
+
+// Register 注册导出格式，名称重复时覆盖
+func Register(e Exporter) {
+	registry[strings.ToLower(e.Name())] = e
+}
+
+// Get 按名称查找导出格式
+func Get(name string) (Exporter, bool) {
+	e, ok := registry[strings.ToLower(strings.TrimSpace(name))]
+	return e, ok
+}
+
+// Names 返回已注册的格式名称（按字母序）
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve 解析逗号分隔的格式列表，如 "csv,ics,json"
+func Resolve(list string) ([]Exporter, error) {
+	var exporters []Exporter
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		e, ok := Get(name)
+		if !ok {
+			return nil, fmt.Errorf("不支持的导出格式 %q（可选: %s）", name, strings.Join(Names(), ", "))
+		}
+		seen[name] = true
+		exporters = append(exporters, e)
+	}
+	if len(exporters) == 0 {
+		return nil, fmt.Errorf("未指定导出格式")
+	}
+	return exporters, nil
+}
+
+// FormatFilename 按模板生成文件名（不含扩展名）
+// 支持的占位符: {term} 学期代码, {student} 学号, {date} 导出日期 (YYYYMMDD)
+func FormatFilename(tmpl string, t *Timetable, now time.Time) string {
+	student := t.StudentID
+	if student == "" {
+		student = "unknown"
+	}
+	r := strings.NewReplacer(
+		"{term}", t.Term,
+		"{student}", student,
+		"{date}", now.Format("20060102"),
+	)
+	return r.Replace(tmpl)
+}
+
+// WriteFile 以指定格式导出到 dir/name.<ext>，返回写入的文件路径
+func WriteFile(dir, name string, e Exporter, t *Timetable) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	path := filepath.Join(dir, name+"."+e.Ext())
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("创建文件失败: %w", err)
+	}
+	if err := e.Write(f, t); err != nil {
+		f.Close()
+		return "", fmt.Errorf("导出 %s 失败: %w", e.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func init() {
+	Register(htmlExporter{})
+}
+
+// htmlExporter 导出可直接在浏览器中打开或打印的周课表网页
+type htmlExporter struct{}
+
+func (htmlExporter) Name() string { return "html" }
+func (htmlExporter) Ext() string  { return "html" }
+
+// htmlCell 课表格子中的一门课
+type htmlCell struct {
+	Name, Sections, Teacher, Location, Weeks string
+}
+
+// htmlRow 课表的一行（一节课）
+type htmlRow struct {
+	Section    int
+	Begin, End string
+	Days       [][]htmlCell
+}
+
+// htmlItem 课表之外单独列出的课程
+type htmlItem struct {
+	Name   string
+	Detail string
+}
+
+var htmlPage = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>BISTU 课表 {{.Term}}</title>
+<style>
+body { font-family: sans-serif; margin: 1.5em; }
+table { border-collapse: collapse; width: 100%; table-layout: fixed; }
+th, td { border: 1px solid #ccc; padding: 4px; vertical-align: top; font-size: 13px; }
+th.section { width: 6em; font-weight: normal; color: #666; }
+.course { background: #eef4ff; border-radius: 4px; padding: 3px; margin-bottom: 3px; }
+.course b { display: block; }
+.meta { color: #555; }
+</style>
+</head>
+<body>
+<h1>BISTU 课表 {{.Term}}</h1>
+{{- if .Start}}
+<p>第一周周一：{{.Start}}</p>
+{{- end}}
+<table>
+<tr><th class="section"></th>{{range .Days}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr><th class="section">第{{.Section}}节<br>{{.Begin}}-{{.End}}</th>
+{{- range .Days}}<td>{{range .}}<div class="course"><b>{{.Name}}</b><span class="meta">{{.Sections}} · {{.Location}}<br>{{.Teacher}} · 第{{.Weeks}}周</span></div>{{end}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- if .Unscheduled}}
+<h2>未安排时间的课程</h2>
+<ul>
+{{- range .Unscheduled}}
+<li><b>{{.Name}}</b>{{if .Detail}} <span class="meta">{{.Detail}}</span>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Unplaced}}
+<h2>星期或节次无法识别的课程</h2>
+<ul>
+{{- range .Unplaced}}
+<li><b>{{.Name}}</b> <span class="meta">{{.Detail}}</span></li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+func (htmlExporter) Write(w io.Writer, t *Timetable) error {
+	days := 5
+	var unplaced []htmlItem
+	rows := make([]htmlRow, len(schedule.SectionTimes))
+	for i, st := range schedule.SectionTimes {
+		rows[i] = htmlRow{Section: i + 1, Begin: st.Begin, End: st.End, Days: make([][]htmlCell, 7)}
+	}
+	for _, c := range t.Courses {
+		day, begin, end, err := c.Slot()
+		if err != nil || day < 1 || day > 7 || begin < 1 || end > len(rows) {
+			unplaced = append(unplaced, htmlItem{c.Name, strings.Join([]string{c.DayOfWeek, c.BeginSection + "-" + c.EndSection, c.Teacher, c.Location, c.Weeks}, " · ")})
+			continue
+		}
+		if day > days {
+			days = 7
+		}
+		rows[begin-1].Days[day-1] = append(rows[begin-1].Days[day-1], htmlCell{
+			Name: c.Name, Sections: c.BeginSection + "-" + c.EndSection + "节",
+			Teacher: c.Teacher, Location: c.Location, Weeks: c.Weeks,
+		})
+	}
+	for i := range rows {
+		rows[i].Days = rows[i].Days[:days]
+	}
+
+	var names []string
+	for d := 1; d <= days; d++ {
+		names = append(names, schedule.WeekdayName(d))
+	}
+	var unscheduled []htmlItem
+	for _, u := range t.Unscheduled {
+		unscheduled = append(unscheduled, htmlItem{u.Name, strings.ReplaceAll(unscheduledInfo(u), "\n", " · ")})
+	}
+	start := ""
+	if !t.StartDate.IsZero() {
+		start = t.StartDate.In(schedule.TZ).Format("2006-01-02")
+	}
+
+	return htmlPage.Execute(w, struct {
+		Term, Start string
+		Days        []string
+		Rows        []htmlRow
+		Unscheduled []htmlItem
+		Unplaced    []htmlItem
+	}{t.Term, start, names, rows, unscheduled, unplaced})
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func TestHTMLExport(t *testing.T) {
+	tt := &Timetable{
+		Term: "2025-2026-1",
+		Courses: []schedule.Course{
+			{Name: "高等数学", DayOfWeek: "3", BeginSection: "3", EndSection: "4", Teacher: "张三", Location: "3-101", Weeks: "1-16"},
+			{Name: "<实验>", DayOfWeek: "无", BeginSection: "无", EndSection: "无", Teacher: "无", Location: "无", Weeks: "无"},
+		},
+		Unscheduled: []schedule.Unscheduled{{Name: "专业实习", Teacher: "李四", Credits: 2, Weeks: "17-18", Remark: "集中实习"}},
+	}
+	var buf bytes.Buffer
+	if err := (htmlExporter{}).Write(&buf, tt); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<th>周三</th>",
+		"<b>高等数学</b>",
+		"未安排时间的课程",
+		"<b>专业实习</b>",
+		"老师: 李四 · 学分: 2 · 周数: 17-18 · 备注: 集中实习",
+		"&lt;实验&gt;", // 无法放入格子的课程单独列出，并转义
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	// 周末没课时只列周一到周五
+	if strings.Contains(out, "<th>周六</th>") {
+		t.Errorf("output contains weekend columns")
+	}
+}
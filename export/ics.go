@@ -0,0 +1,189 @@
+package export
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func init() {
+	Register(icsExporter{})
+}
+
+// icsExporter 导出 iCalendar 日历，每次上课对应一个 VEVENT
+type icsExporter struct{}
+
+func (icsExporter) Name() string { return "ics" }
+func (icsExporter) Ext() string  { return "ics" }
+
+func (icsExporter) Write(w io.Writer, t *Timetable) error {
+	if t.StartDate.IsZero() {
+		return fmt.Errorf("ICS 导出需要学期开始日期，请通过 --start 指定")
+	}
+
+	bw := bufio.NewWriter(w)
+	line := func(s string) { bw.WriteString(foldLine(s) + "\r\n") }
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//bistu-wakeup//课表导出//ZH")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escapeText("BISTU 课表 "+t.Term))
+	line("X-WR-TIMEZONE:Asia/Shanghai")
+
+	for _, s := range t.Sessions() {
+		c := s.Course
+		line("BEGIN:VEVENT")
+		line("UID:" + sessionUID(t, s))
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + s.Start.UTC().Format("20060102T150405Z"))
+		line("DTEND:" + s.End.UTC().Format("20060102T150405Z"))
+		line("SUMMARY:" + escapeText(c.Name))
+		if s.Location != "" && s.Location != "无" {
+			line("LOCATION:" + escapeText(s.Location))
+		}
+		desc := fmt.Sprintf("第%d周 第%d-%d节\n老师: %s", s.Week, s.BeginSection, s.EndSection, c.Teacher)
+		if info := courseInfo(c); info != "" {
+			desc += "\n" + info
+		}
+		if s.Note != "" {
+			desc += "\n" + s.Note
+		}
+		line("DESCRIPTION:" + escapeText(desc))
+		line("END:VEVENT")
+	}
+
+	// 学期内的假期和调休以全天日程标出
+	termEnd := t.StartDate.AddDate(0, 0, schedule.MaxWeek(t.Courses)*7)
+	for _, h := range t.Calendar.HolidaysBetween(t.StartDate, termEnd) {
+		from, _ := schedule.ParseDate(h.From)
+		to, _ := schedule.ParseDate(h.To)
+		allDay(line, stamp, t.Term+"|holiday|"+h.From, from, to, h.Name+" 放假")
+	}
+	for _, w := range t.Calendar.WorkdaysBetween(t.StartDate, termEnd) {
+		day, _ := schedule.ParseDate(w.Date)
+		summary := w.Name + " 上课"
+		if w.Follows != "" {
+			follows, _ := schedule.ParseDate(w.Follows)
+			summary = fmt.Sprintf("%s 按%s（%s）课表上课", w.Name, schedule.WeekdayName(schedule.Weekday(follows)), follows.Format("01-02"))
+		}
+		allDay(line, stamp, t.Term+"|workday|"+w.Date, day, day, summary)
+	}
+
+	// 未安排时间的课程没有具体日期，以待办事项列出
+	for _, u := range t.Unscheduled {
+		sum := sha1.Sum([]byte(strings.Join([]string{t.Term, t.StudentID, "todo", u.Name, u.Code}, "|")))
+		line("BEGIN:VTODO")
+		line("UID:" + hex.EncodeToString(sum[:10]) + "@bistu-wakeup")
+		line("DTSTAMP:" + stamp)
+		line("SUMMARY:" + escapeText(u.Name+"（未安排时间）"))
+		if desc := unscheduledInfo(u); desc != "" {
+			line("DESCRIPTION:" + escapeText(desc))
+		}
+		line("CATEGORIES:" + escapeText("未安排"))
+		line("END:VTODO")
+	}
+
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// courseInfo 返回课程号、学分等附加信息，如 "课程号: 1234 · 学分: 4 · 必修"
+func courseInfo(c *schedule.Course) string {
+	var parts []string
+	if c.Code != "" {
+		parts = append(parts, "课程号: "+c.Code)
+	}
+	if c.Credits > 0 {
+		parts = append(parts, "学分: "+strconv.FormatFloat(c.Credits, 'f', -1, 64))
+	}
+	for _, v := range []string{c.CourseType, c.Assessment, c.Campus} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// unscheduledInfo 返回未安排课程的教师、学分、周数和备注，每项一行
+func unscheduledInfo(u schedule.Unscheduled) string {
+	var lines []string
+	if u.Teacher != "" && u.Teacher != "无" {
+		lines = append(lines, "老师: "+u.Teacher)
+	}
+	if u.Code != "" {
+		lines = append(lines, "课程号: "+u.Code)
+	}
+	if u.Credits > 0 {
+		lines = append(lines, "学分: "+strconv.FormatFloat(u.Credits, 'f', -1, 64))
+	}
+	if u.Weeks != "" {
+		lines = append(lines, "周数: "+u.Weeks)
+	}
+	if u.Remark != "" {
+		lines = append(lines, "备注: "+u.Remark)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// allDay 写入 from 至 to（含）的全天日程，不占用忙碌时间
+func allDay(line func(string), stamp, key string, from, to time.Time, summary string) {
+	sum := sha1.Sum([]byte(key))
+	line("BEGIN:VEVENT")
+	line("UID:" + hex.EncodeToString(sum[:10]) + "@bistu-wakeup")
+	line("DTSTAMP:" + stamp)
+	line("DTSTART;VALUE=DATE:" + from.Format("20060102"))
+	line("DTEND;VALUE=DATE:" + to.AddDate(0, 0, 1).Format("20060102"))
+	line("SUMMARY:" + escapeText(summary))
+	line("TRANSP:TRANSPARENT")
+	line("END:VEVENT")
+}
+
+// sessionUID 为每次上课生成稳定的 UID，重复导入时日历应用可据此更新而非重复添加
+func sessionUID(t *Timetable, s schedule.Session) string {
+	c := s.Course
+	key := strings.Join([]string{
+		t.Term, t.StudentID, c.Name, c.DayOfWeek, c.BeginSection, c.EndSection,
+		s.Start.Format("20060102"),
+	}, "|")
+	// 调课、补课可能与正常上课落在同一天，加上实际节次区分
+	if s.Note != "" {
+		key += fmt.Sprintf("|%d-%d", s.BeginSection, s.EndSection)
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:10]) + "@bistu-wakeup"
+}
+
+// escapeText 按 RFC 5545 转义文本值
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// foldLine 按 RFC 5545 将超过 75 字节的行折叠，避免截断多字节字符
+func foldLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > limit {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"高等数学", "高等数学"},
+		{"3-101,3-102", `3-101\,3-102`},
+		{"周一;周三", `周一\;周三`},
+		{`a\b`, `a\\b`},
+		{"第1周\n老师: 张三", `第1周\n老师: 张三`},
+		{"第1周\r\n老师: 张三", `第1周\n老师: 张三`},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.in); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"短行", "SUMMARY:高等数学"},
+		{"ASCII", "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{"中文（每字 3 字节）", "SUMMARY:" + strings.Repeat("高等数学", 30)},
+		{"混合宽度", "LOCATION:" + strings.Repeat("小营校区3-101 🏫 ", 12)},
+	}
+	for _, tt := range tests {
+		got := foldLine(tt.in)
+		lines := strings.Split(got, "\r\n")
+		var joined strings.Builder
+		for i, line := range lines {
+			if len(line) > 75 {
+				t.Errorf("%s: line %d is %d bytes", tt.name, i, len(line))
+			}
+			if !utf8.ValidString(line) {
+				t.Errorf("%s: line %d splits a UTF-8 sequence: %q", tt.name, i, line)
+			}
+			if i > 0 {
+				if !strings.HasPrefix(line, " ") {
+					t.Errorf("%s: continuation line %d does not start with a space", tt.name, i)
+				}
+				line = line[1:]
+			}
+			joined.WriteString(line)
+		}
+		// 展开后与原文相同
+		if joined.String() != tt.in {
+			t.Errorf("%s: unfolded %q, want %q", tt.name, joined.String(), tt.in)
+		}
+		if len(tt.in) <= 75 && got != tt.in {
+			t.Errorf("%s: short line changed to %q", tt.name, got)
+		}
+	}
+}
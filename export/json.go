@@ -0,0 +1,42 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+func init() {
+	Register(jsonExporter{})
+}
+
+// jsonExporter 导出结构化 JSON，便于脚本和其他工具读取
+type jsonExporter struct{}
+
+func (jsonExporter) Name() string { return "json" }
+func (jsonExporter) Ext() string  { return "json" }
+
+func (jsonExporter) Write(w io.Writer, t *Timetable) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	// entities 按教学班归并后的课程，便于按课程而非按记录处理；读取时忽略
+	return enc.Encode(struct {
+		*Timetable
+		Entities []schedule.CourseEntity `json:"entities"`
+	}{t, schedule.GroupCourses(t.Courses, t.Locations)})
+}
+
+// ReadJSON 读取 json 格式导出的课表
+func ReadJSON(r io.Reader) (*Timetable, error) {
+	var t Timetable
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+	if t.Courses == nil {
+		return nil, fmt.Errorf("JSON 中没有 courses 字段，请确认是本工具导出的文件")
+	}
+	return &t, nil
+}
@@ -3,7 +3,10 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 	"github.com/manifoldco/promptui"
 
 	"github.com/bistu-wakeup/bistu-wakeup/auth"
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
 	"github.com/bistu-wakeup/bistu-wakeup/export"
 	"github.com/bistu-wakeup/bistu-wakeup/schedule"
 )
@@ -29,6 +33,18 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := findCommand(os.Args[1]); ok {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				if err != flag.ErrHelp {
+					fmt.Fprintf(os.Stderr, "%s %s\n", color.RedString("✗"), err)
+				}
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	printBanner()
 
 	if err := run(); err != nil {
@@ -46,35 +62,295 @@ func printBanner() {
 	fmt.Println()
 }
 
+// options 命令行参数
+type options struct {
+	cookie    string
+	term      string
+	formats   string
+	outDir    string
+	nameTmpl  string
+	start     string
+	fromCSV   string
+	fromRaw   string
+	saveRaw   string
+	student   string
+	refresh   bool
+	ttl       time.Duration
+	qr        bool
+	qrTimeout time.Duration
+	overrides string
+	split     bool
+	noMerge   bool
+}
+
 func run() error {
-	cookieStr := flag.String("cookie", "", "使用 Cookie 模式（高级用户）")
+	var opts options
+	flag.StringVar(&opts.cookie, "cookie", "", "使用 Cookie 模式（高级用户）")
+	flag.StringVar(&opts.term, "term", "", "学期代码，如 2025-2026-2（不指定则交互选择）")
+	flag.StringVar(&opts.formats, "format", "csv", "导出格式，逗号分隔（可选: "+strings.Join(export.Names(), ", ")+"）")
+	flag.StringVar(&opts.outDir, "out", ".", "输出目录")
+	flag.StringVar(&opts.nameTmpl, "name", "schedule_{term}", "文件名模板，支持 {term} {student} {date}")
+	flag.StringVar(&opts.start, "start", "", "学期第一周周一的日期 (YYYY-MM-DD)，ICS 导出使用")
+	flag.StringVar(&opts.fromCSV, "from-csv", "", "离线模式：从已导出的 WakeUp CSV 读取课表，无需登录")
+	flag.StringVar(&opts.fromRaw, "from-raw", "", "离线模式：从原始数据文件（--save-raw 或接口响应）解析课表")
+	flag.StringVar(&opts.saveRaw, "save-raw", "", "将课表接口的原始数据脱敏后保存到指定文件")
+	flag.StringVar(&opts.student, "student", "", "学号，配合 --term 可直接使用本地缓存")
+	flag.BoolVar(&opts.refresh, "refresh", false, "忽略本地缓存，强制从教务系统重新获取")
+	flag.DurationVar(&opts.ttl, "ttl", cache.DefaultTTL, "本地缓存有效期")
+	flag.BoolVar(&opts.qr, "qr", false, "导出后在局域网内提供下载，并显示二维码供手机扫描")
+	flag.DurationVar(&opts.qrTimeout, "qr-timeout", 5*time.Minute, "局域网下载的等待时间")
+	flag.BoolVar(&opts.split, "split-teachers", false, "不同周由不同教师授课的课程拆分为多条，分别显示教师")
+	flag.BoolVar(&opts.noMerge, "no-merge", false, "不合并同一门课相邻的节次记录")
+	flag.StringVar(&opts.overrides, "overrides", "", "覆盖规则文件 (YAML)，默认读取配置目录下的 overrides.yaml")
 	flag.Parse()
 
+	exporters, err := export.Resolve(opts.formats)
+	if err != nil {
+		return err
+	}
+
+	var (
+		timetable *export.Timetable
+		entry     *cache.Entry
+		store     *cache.Store
+	)
+	switch {
+	case opts.fromCSV != "":
+		timetable, err = loadCSV(opts)
+	case opts.fromRaw != "":
+		timetable, err = loadRaw(opts)
+	default:
+		if store, err = cache.Open(""); err != nil {
+			return err
+		}
+		if entry, err = loadOrFetch(opts, store); err != nil {
+			return err
+		}
+		if opts.saveRaw != "" {
+			if err := schedule.SaveRaw(opts.saveRaw, entry.Term, entry.StudentID, entry.Data()); err != nil {
+				return err
+			}
+			fmt.Printf("    %s 原始数据已脱敏保存到 %s\n\n", green("✓"), bold(displayPath(opts.saveRaw)))
+		}
+		timetable = entryTimetable(entry)
+		timetable.Exceptions, err = store.Exceptions(entry.StudentID, entry.Term)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.split {
+		timetable.Courses = schedule.SplitGroups(timetable.Courses)
+	}
+
+	// 从 CSV 读取的课表已是导出结果，不再重复应用覆盖规则
+	if opts.fromCSV == "" {
+		rules, path, err := loadOverrides(opts.overrides)
+		if err != nil {
+			return err
+		}
+		if rules != nil {
+			timetable.Courses = rules.Apply(timetable.Courses)
+			fmt.Printf("    %s 已应用覆盖规则 %s\n\n", green("✓"), bold(displayPath(path)))
+		}
+	}
+
+	if err := prepare(timetable, !opts.noMerge); err != nil {
+		return err
+	}
+	timetable.StartDate, err = termStart(timetable, opts.start, exporters)
+	if err != nil {
+		return err
+	}
+	// 记住用户指定的开学日期，供离线命令使用
+	if entry != nil && opts.start != "" && !entry.StartDate.Equal(timetable.StartDate) {
+		entry.StartDate = timetable.StartDate
+		if err := store.Save(entry); err != nil {
+			return err
+		}
+	}
+
+	// 导出前提示时间冲突（冲突的课程在 WakeUp 中会互相覆盖）和来不及赶路的课间
+	if conflicts := schedule.FindConflicts(timetable.Courses); len(conflicts) > 0 {
+		printConflicts(timetable.Courses, conflicts, opts.fromCSV == "")
+	}
+	if warnings := schedule.FindTravelWarnings(timetable.Courses, timetable.Locations); len(warnings) > 0 {
+		printTravelWarnings(timetable.Courses, warnings)
+	}
+	if len(timetable.Rejected) > 0 {
+		printRejected(os.Stdout, timetable.Rejected)
+	}
+	// WakeUp 的 CSV 只能表示有固定时间的课程，未安排的课程在这里单独列出
+	if len(timetable.Unscheduled) > 0 {
+		printUnscheduled(timetable.Unscheduled)
+	}
+
+	name := export.FormatFilename(opts.nameTmpl, timetable, time.Now())
+	paths := make([]string, 0, len(exporters))
+	for _, e := range exporters {
+		path, err := export.WriteFile(opts.outDir, name, e, timetable)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+
+	// 完成
+	fmt.Println(cyan("  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	fmt.Printf("\n  %s %s\n", green("✓"), bold("导出成功!"))
+	for _, path := range paths {
+		fmt.Printf("    %s %s\n", magenta("📄"), bold(displayPath(path)))
+	}
+	fmt.Printf("    %s %d 门课程\n\n", blue("📊"), len(timetable.Courses))
+	fmt.Printf("  %s\n", dim("💡 提示: 打开 WakeUp → 导入课表 → 选择此文件"))
+	fmt.Println()
+
+	if opts.qr {
+		return shareOnLAN(pickShareFile(paths), opts.qrTimeout)
+	}
+	return nil
+}
+
+// printRejected 列出星期或节次无法识别、没有写入课表的记录
+func printRejected(w io.Writer, list []schedule.Rejected) {
+	fmt.Fprintf(w, "    %s %s 条记录的星期或节次无法识别，未写入课表:\n", yellow("⚠"), bold(fmt.Sprintf("%d", len(list))))
+	fromRaw := false
+	for _, r := range list {
+		fmt.Fprintf(w, "      %s %s\n", yellow("!"), r)
+		fromRaw = fromRaw || r.Record > 0
+	}
+	if fromRaw {
+		fmt.Fprintf(w, "    %s\n", dim("可用 --save-raw 保存脱敏后的原始数据并反馈"))
+	}
+	fmt.Fprintln(w)
+}
+
+// printUnscheduled 列出未安排时间的课程，这些课程不会出现在 WakeUp 课表中
+func printUnscheduled(list []schedule.Unscheduled) {
+	fmt.Printf("    %s %s 门课程未安排上课时间，不会出现在 WakeUp 课表中（ICS 中为待办事项）:\n",
+		blue("ℹ"), bold(fmt.Sprintf("%d", len(list))))
+	for _, u := range list {
+		fmt.Printf("      %s %s\n", dim("·"), u)
+		if u.Remark != "" {
+			fmt.Printf("        %s\n", dim(u.Remark))
+		}
+	}
+	fmt.Println()
+}
+
+// pickShareFile 优先分享 WakeUp 使用的 CSV
+func pickShareFile(paths []string) string {
+	for _, p := range paths {
+		if strings.EqualFold(filepath.Ext(p), ".csv") {
+			return p
+		}
+	}
+	return paths[0]
+}
+
+// loadOrFetch 优先使用未过期的本地缓存，否则登录教务系统获取
+func loadOrFetch(opts options, store *cache.Store) (*cache.Entry, error) {
+	if !opts.refresh {
+		if entry := freshEntry(opts, store); entry != nil {
+			fmt.Printf("    %s 使用本地缓存: %s %s（%s前获取）\n", green("✓"),
+				bold(entry.StudentID), schedule.FormatTermLabel(entry.Term, false), formatAge(entry.Age(time.Now())))
+			fmt.Printf("    %s\n\n", dim("其他学期请使用 --term，强制刷新请使用 --refresh"))
+			return entry, nil
+		}
+	}
+	return fetchOnline(opts, store)
+}
+
+// freshEntry 在无需登录的情况下查找可用缓存
+// 学号取 --student 或最近一次使用的学号，学期取 --term 或最近一次使用的学期
+// 两者都未指定时返回 nil，仍然登录并选择学期，避免新学期开始后一直导出旧学期
+func freshEntry(opts options, store *cache.Store) *cache.Entry {
+	if opts.student == "" && opts.term == "" {
+		return nil
+	}
+	last, err := store.Latest()
+	if err != nil {
+		last = nil
+	}
+
+	studentID, term := opts.student, opts.term
+	if last != nil {
+		if studentID == "" {
+			studentID = last.StudentID
+		}
+		if term == "" && studentID == last.StudentID {
+			term = last.Term
+		}
+	}
+	if studentID == "" || term == "" {
+		return nil
+	}
+
+	entry, err := store.Load(studentID, term)
+	if err != nil || entry.Stale(opts.ttl, time.Now()) {
+		return nil
+	}
+	return entry
+}
+
+// formatAge 将时长格式化为 "3 分钟" 这类简短描述
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "不到 1 分钟"
+	case d < time.Hour:
+		return fmt.Sprintf("%d 分钟", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%d 小时", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d 天", int(d.Hours()/24))
+	}
+}
+
+// entryTimetable 将缓存条目转换为导出数据
+func entryTimetable(e *cache.Entry) *export.Timetable {
+	return &export.Timetable{
+		Term:        e.Term,
+		StudentID:   e.StudentID,
+		StartDate:   e.StartDate,
+		Courses:     e.Courses,
+		Unscheduled: e.Unscheduled,
+		Rejected:    e.Rejected,
+	}
+}
+
+// fetchOnline 登录教务系统并获取课表，成功后写入缓存
+func fetchOnline(opts options, store *cache.Store) (*cache.Entry, error) {
 	// 1. 认证
 	client, err := auth.NewClient()
 	if err != nil {
-		return fmt.Errorf("初始化失败: %w", err)
+		return nil, fmt.Errorf("初始化失败: %w", err)
 	}
 
 	printStep(1, 4, "身份认证")
-	if *cookieStr != "" {
+	if opts.cookie != "" {
 		fmt.Printf("    %s 使用 Cookie 模式\n", blue("→"))
-		if err := client.CookieLogin("https://jwxt.bistu.edu.cn", *cookieStr); err != nil {
-			return err
+		if err := client.CookieLogin("https://jwxt.bistu.edu.cn", opts.cookie); err != nil {
+			return nil, err
 		}
 		fmt.Printf("    %s Cookie 已设置\n\n", green("✓"))
 	} else {
 		if err := interactiveLogin(client); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	// 保存会话，供 watch / serve 等命令复用
+	if err := client.SaveSession(store.SessionFile()); err != nil {
+		fmt.Printf("    %s %v\n\n", yellow("⚠"), err)
+	}
+
 	// 2. 获取用户信息
 	printStep(2, 4, "获取用户信息")
 	fetcher := &schedule.Fetcher{Client: client.HTTP}
 	userInfo, err := fetcher.FetchUserInfo()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	welcome := userInfo.StudentID
 	if userInfo.UserName != "" {
@@ -84,42 +360,130 @@ func run() error {
 
 	// 3. 选择学期
 	printStep(3, 4, "选择学期")
-	termCode, err := selectTerm(userInfo)
-	if err != nil {
-		return err
+	termCode := opts.term
+	if termCode == "" {
+		termCode, err = selectTerm(userInfo)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fmt.Printf("    %s %s\n\n", green("✓"), schedule.FormatTermLabel(termCode, false))
 	}
 
 	// 4. 获取课表
 	printStep(4, 4, "获取课表")
-	rawCourses, err := fetcher.FetchSchedule(termCode, userInfo.StudentID)
+	previous, err := store.Load(userInfo.StudentID, termCode)
 	if err != nil {
-		return err
+		previous = nil
+	}
+	if previous != nil && !opts.refresh && !previous.Stale(opts.ttl, time.Now()) {
+		fmt.Printf("    %s 使用本地缓存（%s前获取）\n\n", green("✓"), formatAge(previous.Age(time.Now())))
+		return previous, nil
 	}
-	fmt.Printf("    %s 获取到 %s 门课程\n\n", green("✓"), bold(fmt.Sprintf("%d", len(rawCourses))))
 
-	// 5. 解析并导出
-	courses := schedule.ParseAll(rawCourses)
-	rows := make([][]string, 0, len(courses))
-	for _, c := range courses {
-		rows = append(rows, []string{
-			c.Name, c.DayOfWeek, c.BeginSection,
-			c.EndSection, c.Teacher, c.Location, c.Weeks,
-		})
+	data, err := fetcher.FetchSchedule(termCode, userInfo.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.NotArranged) > 0 {
+		fmt.Printf("    %s 获取到 %s 门课程，另有 %s 门未安排时间\n\n", green("✓"),
+			bold(fmt.Sprintf("%d", len(data.Arranged))), bold(fmt.Sprintf("%d", len(data.NotArranged))))
+	} else {
+		fmt.Printf("    %s 获取到 %s 门课程\n\n", green("✓"), bold(fmt.Sprintf("%d", len(data.Arranged))))
 	}
 
-	filename := fmt.Sprintf("schedule_%s.csv", termCode)
-	if err := export.WriteCSV(filename, rows); err != nil {
-		return err
+	entry := newEntry(userInfo, termCode, data, previous)
+	if previous != nil {
+		printChanges(schedule.Diff(previous.Courses, entry.Courses))
+	}
+	if err := store.Save(entry); err != nil {
+		fmt.Printf("    %s %v\n\n", yellow("⚠"), err)
 	}
+	return entry, nil
+}
 
-	// 完成
-	fmt.Println(cyan("  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
-	fmt.Printf("\n  %s %s\n", green("✓"), bold("导出成功!"))
-	fmt.Printf("    %s %s\n", magenta("📄"), bold("./"+filename))
-	fmt.Printf("    %s %d 门课程\n\n", blue("📊"), len(rawCourses))
-	fmt.Printf("  %s\n", dim("💡 提示: 打开 WakeUp → 导入课表 → 选择此文件"))
-	fmt.Println()
-	return nil
+// loadCSV 离线读取已导出的 CSV
+func loadCSV(opts options) (*export.Timetable, error) {
+	courses, rejected, err := export.ReadCSVFile(opts.fromCSV)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("    %s 从 %s 读取到 %s 门课程\n\n", green("✓"), bold(opts.fromCSV), bold(fmt.Sprintf("%d", len(courses))))
+	return &export.Timetable{
+		Term:     offlineTerm(opts.term, "", opts.fromCSV),
+		Courses:  courses,
+		Rejected: rejected,
+	}, nil
+}
+
+// loadRaw 离线解析原始数据文件
+func loadRaw(opts options) (*export.Timetable, error) {
+	dump, err := schedule.LoadRaw(opts.fromRaw)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("    %s 从 %s 读取到 %s 条原始记录\n\n", green("✓"), bold(opts.fromRaw), bold(fmt.Sprintf("%d", len(dump.Items))))
+	courses, rejected := schedule.ParseRecords(dump.Items)
+	return &export.Timetable{
+		Term:        offlineTerm(opts.term, dump.Term, opts.fromRaw),
+		Courses:     courses,
+		Unscheduled: schedule.ParseAllUnscheduled(dump.NotArranged),
+		Rejected:    rejected,
+	}, nil
+}
+
+var termInNameRe = regexp.MustCompile(`\d{4}-\d{4}-[123]`)
+
+// offlineTerm 离线模式的学期代码：--term > 文件内记录 > 文件名
+func offlineTerm(flagTerm, fileTerm, path string) string {
+	if flagTerm != "" {
+		return flagTerm
+	}
+	if fileTerm != "" {
+		return fileTerm
+	}
+	if term := termInNameRe.FindString(filepath.Base(path)); term != "" {
+		return term
+	}
+	return "offline"
+}
+
+// termStart 确定学期开始日期：优先使用 --start，其次是缓存中记住的日期，否则按惯例推算
+// 只有 ICS 依赖开学日期，仅在导出 ICS 时提示推算结果可能不准、无法推算时报错
+func termStart(t *export.Timetable, startStr string, exporters []export.Exporter) (time.Time, error) {
+	if startStr != "" {
+		return schedule.ParseDate(startStr)
+	}
+	if !t.StartDate.IsZero() {
+		return t.StartDate, nil
+	}
+	needed := false
+	for _, e := range exporters {
+		if e.Name() == "ics" {
+			needed = true
+			break
+		}
+	}
+	start, err := schedule.GuessTermStart(t.Term)
+	if err != nil {
+		if needed {
+			return time.Time{}, fmt.Errorf("无法推算开学日期，请通过 --start 指定: %w", err)
+		}
+		return time.Time{}, nil
+	}
+	if needed {
+		fmt.Printf("    %s 未指定 --start，按惯例推算开学日期为 %s\n\n",
+			yellow("⚠"), bold(start.Format("2006-01-02")))
+	}
+	return start, nil
+}
+
+// displayPath 相对路径统一加上 ./ 前缀，方便用户直接复制
+func displayPath(path string) string {
+	if filepath.IsAbs(path) || strings.HasPrefix(path, ".") {
+		return path
+	}
+	return "./" + path
 }
 
 func printStep(current, total int, title string) {
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// Event 一次课表变更通知
+type Event struct {
+	Time      time.Time         `json:"time"`
+	StudentID string            `json:"studentId"`
+	Term      string            `json:"term"`
+	Changes   []schedule.Change `json:"changes"`
+}
+
+// Summary 返回多行中文摘要，每行一条变更
+func (e Event) Summary() string {
+	lines := make([]string, 0, len(e.Changes)+1)
+	lines = append(lines, fmt.Sprintf("%s 课表有 %d 处变化:", e.Term, len(e.Changes)))
+	for _, c := range e.Changes {
+		lines = append(lines, "  "+c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Sink 通知渠道
+type Sink interface {
+	Name() string
+	Notify(ctx context.Context, e Event) error
+}
+
+// Stdout 将通知打印到终端
+type Stdout struct {
+	W io.Writer
+}
+
+func (s Stdout) Name() string { return "stdout" }
+
+func (s Stdout) Notify(_ context.Context, e Event) error {
+	w := s.W
+	if w == nil {
+		w = os.Stdout
+	}
+	_, err := fmt.Fprintf(w, "[%s] %s\n", e.Time.Format("2006-01-02 15:04"), e.Summary())
+	return err
+}
+
+// Webhook 以 JSON 形式 POST 到指定地址
+// 请求体为 Event，另附 text 字段便于直接转发到聊天机器人
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w Webhook) Name() string { return "webhook" }
+
+func (w Webhook) Notify(ctx context.Context, e Event) error {
+	payload := struct {
+		Event
+		Text string `json:"text"`
+	}{e, e.Summary()}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建 webhook 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 返回 %s", resp.Status)
+	}
+	return nil
+}
+
+// Exec 执行外部命令，事件 JSON 通过标准输入传入
+// 环境变量 BISTU_TERM、BISTU_CHANGES（变更条数）、BISTU_SUMMARY（中文摘要）方便简单脚本使用
+type Exec struct {
+	Command string
+}
+
+func (x Exec) Name() string { return "exec" }
+
+func (x Exec) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", x.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", x.Command)
+	}
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"BISTU_TERM="+e.Term,
+		fmt.Sprintf("BISTU_CHANGES=%d", len(e.Changes)),
+		"BISTU_SUMMARY="+e.Summary(),
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("执行通知命令失败: %w", err)
+	}
+	return nil
+}
+
+// Broadcast 依次发送到所有渠道，返回第一个错误，单个渠道失败不影响其他渠道
+func Broadcast(ctx context.Context, sinks []Sink, e Event) error {
+	var first error
+	for _, s := range sinks {
+		if err := s.Notify(ctx, e); err != nil && first == nil {
+			first = fmt.Errorf("%s: %w", s.Name(), err)
+		}
+	}
+	return first
+}
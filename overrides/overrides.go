@@ -0,0 +1,195 @@
+package overrides
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// Rules 用户维护的课表覆盖规则，在解析之后、导出之前生效
+// 执行顺序：隐藏 → 改名 → 改地点 → 添加自定义课程
+type Rules struct {
+	Hide     []string   `yaml:"hide"`
+	Rename   []Rename   `yaml:"rename"`
+	Location []Relocate `yaml:"location"`
+	Add      []Item     `yaml:"add"`
+
+	hide     []*regexp.Regexp
+	rename   []*regexp.Regexp
+	relocate []relocate
+}
+
+// Rename 将课程名中匹配 Match 的部分替换为 To，可使用 $1 引用分组
+type Rename struct {
+	Match string `yaml:"match"`
+	To    string `yaml:"to"`
+}
+
+// Relocate 修改课程地点，Course 为课程名正则，From 为原地点正则（可选）
+type Relocate struct {
+	Course string `yaml:"course"`
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+}
+
+type relocate struct {
+	course, from *regexp.Regexp
+	to           string
+}
+
+// Item 自定义的周期性日程，如社团活动
+type Item struct {
+	Name     string `yaml:"name"`
+	Day      int    `yaml:"day"`
+	Sections string `yaml:"sections"` // 如 "11-12" 或 "5"
+	Weeks    string `yaml:"weeks"`
+	Location string `yaml:"location"`
+	Teacher  string `yaml:"teacher"`
+}
+
+// DefaultPath 返回默认规则文件路径（用户配置目录下的 overrides.yaml 或 overrides.yml）
+// 都不存在时返回空字符串
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{"overrides.yaml", "overrides.yml"} {
+		path := filepath.Join(dir, "bistu-wakeup", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Load 读取并校验 YAML 格式的规则文件
+func Load(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取覆盖规则失败: %w", err)
+	}
+	r := &Rules{}
+	if err := yaml.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("解析覆盖规则 %s 失败: %w", path, err)
+	}
+	if err := r.compile(); err != nil {
+		return nil, fmt.Errorf("覆盖规则 %s: %w", path, err)
+	}
+	return r, nil
+}
+
+func (r *Rules) compile() error {
+	for i, p := range r.Hide {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("hide 第 %d 条: %w", i+1, err)
+		}
+		r.hide = append(r.hide, re)
+	}
+	for i, rn := range r.Rename {
+		re, err := regexp.Compile(rn.Match)
+		if err != nil {
+			return fmt.Errorf("rename 第 %d 条: %w", i+1, err)
+		}
+		r.rename = append(r.rename, re)
+	}
+	for i, l := range r.Location {
+		rl := relocate{to: l.To}
+		var err error
+		if rl.course, err = regexp.Compile(l.Course); err != nil {
+			return fmt.Errorf("location 第 %d 条: %w", i+1, err)
+		}
+		if l.From != "" {
+			if rl.from, err = regexp.Compile(l.From); err != nil {
+				return fmt.Errorf("location 第 %d 条: %w", i+1, err)
+			}
+		}
+		r.relocate = append(r.relocate, rl)
+	}
+	for i, it := range r.Add {
+		if _, err := it.Course(); err != nil {
+			return fmt.Errorf("add 第 %d 条: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Course 将自定义日程转换为课程
+func (it Item) Course() (schedule.Course, error) {
+	if it.Name == "" {
+		return schedule.Course{}, errors.New("缺少名称")
+	}
+	if it.Day < 1 || it.Day > 7 {
+		return schedule.Course{}, fmt.Errorf("%s: 星期需为 1-7", it.Name)
+	}
+	b, e, err := schedule.ParseSections(it.Sections)
+	if err != nil {
+		return schedule.Course{}, fmt.Errorf("%s: %w", it.Name, err)
+	}
+	if _, err := schedule.ParseWeeks(it.Weeks); err != nil {
+		return schedule.Course{}, fmt.Errorf("%s: %w", it.Name, err)
+	}
+	return schedule.Course{
+		Name:         it.Name,
+		DayOfWeek:    strconv.Itoa(it.Day),
+		BeginSection: strconv.Itoa(b),
+		EndSection:   strconv.Itoa(e),
+		Teacher:      orNone(it.Teacher),
+		Location:     orNone(it.Location),
+		Weeks:        it.Weeks,
+	}, nil
+}
+
+// orNone 与解析教务数据、读取 CSV 时一致，未填写的字段记为 "无"
+func orNone(s string) string {
+	if s = strings.TrimSpace(s); s == "" {
+		return "无"
+	}
+	return s
+}
+
+// Apply 返回应用规则后的课程列表，不修改传入的切片
+// 隐藏规则按教务系统中的原始课程名匹配，地点规则按改名后的课程名匹配
+func (r *Rules) Apply(courses []schedule.Course) []schedule.Course {
+	if r == nil {
+		return courses
+	}
+	out := make([]schedule.Course, 0, len(courses)+len(r.Add))
+	for _, c := range courses {
+		if r.hidden(c.Name) {
+			continue
+		}
+		for i, re := range r.rename {
+			c.Name = strings.TrimSpace(re.ReplaceAllString(c.Name, r.Rename[i].To))
+		}
+		for _, rl := range r.relocate {
+			if rl.course.MatchString(c.Name) && (rl.from == nil || rl.from.MatchString(c.Location)) {
+				c.Location = rl.to
+			}
+		}
+		out = append(out, c)
+	}
+	for _, it := range r.Add {
+		c, _ := it.Course()
+		out = append(out, c)
+	}
+	return out
+}
+
+func (r *Rules) hidden(name string) bool {
+	for _, re := range r.hide {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
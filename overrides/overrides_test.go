@@ -0,0 +1,112 @@
+package overrides
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// load 将 YAML 写入临时文件后读取
+func load(t *testing.T, content string) (*Rules, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path)
+}
+
+func course(name, location string) schedule.Course {
+	return schedule.Course{
+		Name: name, DayOfWeek: "1", BeginSection: "1", EndSection: "2",
+		Teacher: "张三", Location: location, Weeks: "1-16",
+	}
+}
+
+func TestApply(t *testing.T) {
+	courses := []schedule.Course{
+		course("形势与政策（一）", "1-101"),
+		course("大学英语（二）", "3-101"),
+		course("体育（篮球）", "无"),
+		course("高等数学A", "3-101"),
+	}
+	tests := []struct {
+		name  string
+		rules string
+		want  []schedule.Course
+	}{
+		{
+			name:  "隐藏",
+			rules: "hide:\n  - ^形势与政策\n",
+			want:  courses[1:],
+		},
+		{
+			name:  "改名可引用分组，多条规则依次生效",
+			rules: "rename:\n  - match: '^大学(.*)（二）$'\n    to: $1\n  - match: A$\n    to: ''\n",
+			want: []schedule.Course{
+				courses[0], course("英语", "3-101"), courses[2], course("高等数学", "3-101"),
+			},
+		},
+		{
+			name:  "隐藏按原始课程名匹配",
+			rules: "hide:\n  - ^高等数学A$\nrename:\n  - match: A$\n    to: ''\n",
+			want:  courses[:3],
+		},
+		{
+			name: "改地点按改名后的课程名和原地点匹配",
+			rules: "rename:\n  - match: ^体育（(.*)）$\n    to: 体育-$1\n" +
+				"location:\n  - course: ^体育-\n    from: ^无$\n    to: 篮球场\n  - course: 数学\n    from: ^5-\n    to: 不会匹配\n",
+			want: []schedule.Course{
+				courses[0], courses[1], course("体育-篮球", "篮球场"), courses[3],
+			},
+		},
+		{
+			name:  "添加自定义日程，未填写的字段记为无",
+			rules: "add:\n  - name: 社团活动\n    day: 3\n    sections: 11-12\n    weeks: 1-8\n",
+			want: append(append([]schedule.Course(nil), courses...), schedule.Course{
+				Name: "社团活动", DayOfWeek: "3", BeginSection: "11", EndSection: "12",
+				Teacher: "无", Location: "无", Weeks: "1-8",
+			}),
+		},
+	}
+	for _, tt := range tests {
+		r, err := load(t, tt.rules)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := r.Apply(courses); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %+v\n want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	// 不修改传入的切片
+	if courses[1].Name != "大学英语（二）" {
+		t.Errorf("Apply modified its input: %+v", courses[1])
+	}
+	// 没有规则时原样返回
+	var none *Rules
+	if got := none.Apply(courses); !reflect.DeepEqual(got, courses) {
+		t.Errorf("nil Rules: got %+v", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, content := range []string{
+		"hide: [\n",                            // YAML 格式错误
+		"hide:\n  - '('\n",                     // 正则错误
+		"rename:\n  - match: '['\n    to: x\n", // 正则错误
+		"location:\n  - course: x\n    from: '('\n    to: y\n",
+		"add:\n  - day: 1\n    sections: 1-2\n    weeks: 1-16\n",              // 缺少名称
+		"add:\n  - name: x\n    day: 8\n    sections: 1-2\n    weeks: 1-16\n", // 星期超出范围
+		"add:\n  - name: x\n    day: 1\n    sections: 15\n    weeks: 1-16\n",  // 节次超出作息表
+		"add:\n  - name: x\n    day: 1\n    sections: 1-2\n    weeks: 全学期\n",  // 周数无法解析
+	} {
+		if r, err := load(t, content); err == nil {
+			t.Errorf("Load(%q) = %+v, want error", content, r)
+		}
+	}
+}
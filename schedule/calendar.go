@@ -0,0 +1,165 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TZ 教务系统所在时区（北京时间）
+// 使用固定时区，避免 Windows 等缺少 tzdata 的环境无法加载 Asia/Shanghai
+var TZ = time.FixedZone("CST", 8*3600)
+
+// SectionTime 单节课的上下课时间（格式 HH:MM）
+type SectionTime struct {
+	Begin string `json:"begin"`
+	End   string `json:"end"`
+}
+
+// SectionTimes BISTU 作息时间表，下标 0 对应第 1 节
+var SectionTimes = []SectionTime{
+	{"08:00", "08:45"},
+	{"08:50", "09:35"},
+	{"09:50", "10:35"},
+	{"10:40", "11:25"},
+	{"11:30", "12:15"},
+	{"13:00", "13:45"},
+	{"13:50", "14:35"},
+	{"14:45", "15:30"},
+	{"15:40", "16:25"},
+	{"16:30", "17:15"},
+	{"17:20", "18:05"},
+	{"18:30", "19:15"},
+	{"19:20", "20:05"},
+	{"20:10", "20:55"},
+}
+
+// SectionSpan 返回第 begin 至第 end 节在指定日期的起止时间（默认作息表）
+func SectionSpan(date time.Time, begin, end int) (time.Time, time.Time, error) {
+	return sectionSpan(SectionTimes, date, begin, end)
+}
+
+func sectionSpan(times []SectionTime, date time.Time, begin, end int) (time.Time, time.Time, error) {
+	if begin < 1 || end < begin || end > len(times) {
+		return time.Time{}, time.Time{}, fmt.Errorf("节次超出作息表范围: %d-%d", begin, end)
+	}
+	start, err := atClock(date, times[begin-1].Begin)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	stop, err := atClock(date, times[end-1].End)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, stop, nil
+}
+
+// validateSectionTimes 检查自定义作息表：节数与默认作息表相同，时间为 HH:MM 且依次递增
+func validateSectionTimes(times []SectionTime) error {
+	if len(times) != len(SectionTimes) {
+		return fmt.Errorf("需要 %d 节，实际 %d 节", len(SectionTimes), len(times))
+	}
+	prev := ""
+	for i, st := range times {
+		for _, clock := range []string{st.Begin, st.End} {
+			if _, err := time.Parse("15:04", clock); err != nil || len(clock) != 5 {
+				return fmt.Errorf("第 %d 节时间应为 HH:MM: %q", i+1, clock)
+			}
+		}
+		if st.Begin >= st.End || st.Begin < prev {
+			return fmt.Errorf("第 %d 节时间不是依次递增: %s-%s", i+1, st.Begin, st.End)
+		}
+		prev = st.End
+	}
+	return nil
+}
+
+func atClock(date time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的时间 %q: %w", clock, err)
+	}
+	y, m, d := date.In(TZ).Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, TZ), nil
+}
+
+// ParseDate 解析 YYYY-MM-DD 格式的日期（北京时间）
+func ParseDate(s string) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(s), TZ)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日期格式应为 YYYY-MM-DD: %q", s)
+	}
+	return t, nil
+}
+
+// GuessTermStart 按惯例推算学期第一周的周一
+// 仅作兜底，校历公布后应通过 --start 指定准确日期
+//   - 第一学期：9 月 1 日之后的第一个周一
+//   - 第二学期：2 月 24 日之后的第一个周一
+//   - 小学期：  7 月 1 日之后的第一个周一
+func GuessTermStart(code string) (time.Time, error) {
+	parts := strings.Split(code, "-")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("无效的学期代码: %s", code)
+	}
+	startYear, err1 := strconv.Atoi(parts[0])
+	endYear, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return time.Time{}, fmt.Errorf("无效的学期代码: %s", code)
+	}
+
+	var anchor time.Time
+	switch parts[2] {
+	case "1":
+		anchor = time.Date(startYear, time.September, 1, 0, 0, 0, 0, TZ)
+	case "2":
+		anchor = time.Date(endYear, time.February, 24, 0, 0, 0, 0, TZ)
+	case "3":
+		anchor = time.Date(endYear, time.July, 1, 0, 0, 0, 0, TZ)
+	default:
+		return time.Time{}, fmt.Errorf("无效的学期代码: %s", code)
+	}
+	return nextMonday(anchor), nil
+}
+
+func nextMonday(t time.Time) time.Time {
+	offset := (int(time.Monday) - int(t.Weekday()) + 7) % 7
+	return t.AddDate(0, 0, offset)
+}
+
+// WeekOf 返回日期所在的教学周（第一周为 1，开学前返回 0 或负数）
+func WeekOf(termStart, date time.Time) int {
+	start := dayOf(termStart)
+	days := int(dayOf(date).Sub(start).Hours() / 24)
+	if days < 0 {
+		// 向下取整，开学前一周为 0
+		return (days-6)/7 + 1
+	}
+	return days/7 + 1
+}
+
+// Weekday 返回 1-7 表示的星期（周一为 1）
+func Weekday(t time.Time) int {
+	wd := int(t.In(TZ).Weekday())
+	if wd == 0 {
+		return 7
+	}
+	return wd
+}
+
+// dayOf 截断到当天零点（北京时间）
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.In(TZ).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, TZ)
+}
+
+var weekdayNames = []string{"周一", "周二", "周三", "周四", "周五", "周六", "周日"}
+
+// WeekdayName 返回星期的中文名称，如 3 → "周三"
+func WeekdayName(day int) string {
+	if day < 1 || day > 7 {
+		return fmt.Sprintf("星期%d", day)
+	}
+	return weekdayNames[day-1]
+}
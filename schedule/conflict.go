@@ -0,0 +1,111 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Conflict 两门课在同一天的同一节次重叠
+// A、B 为课程在切片中的下标，Weeks 为发生重叠的周次
+type Conflict struct {
+	A     int   `json:"a"`
+	B     int   `json:"b"`
+	Day   int   `json:"day"`
+	Begin int   `json:"begin"`
+	End   int   `json:"end"`
+	Weeks []int `json:"weeks"`
+}
+
+// slot 一周中某天的某一节
+type slot struct {
+	week, day, section int
+}
+
+// FindConflicts 将每门课展开为 周 × 星期 × 节次，找出被两门及以上课程占用的时间
+// 同一对课程在同一天的重叠合并为一条，按星期、节次排序
+func FindConflicts(courses []Course) []Conflict {
+	occupied := make(map[slot][]int)
+	for i, c := range courses {
+		day, begin, end, err := c.Slot()
+		if err != nil {
+			continue
+		}
+		weeks, err := ParseWeeks(c.Weeks)
+		if err != nil {
+			continue
+		}
+		for _, w := range weeks {
+			for sec := begin; sec <= end; sec++ {
+				k := slot{w, day, sec}
+				occupied[k] = append(occupied[k], i)
+			}
+		}
+	}
+
+	type pairKey struct{ a, b, day int }
+	type overlap struct {
+		weeks    map[int]bool
+		sections map[int]bool
+	}
+	pairs := make(map[pairKey]*overlap)
+	for k, idx := range occupied {
+		for x := 0; x < len(idx); x++ {
+			for y := x + 1; y < len(idx); y++ {
+				pk := pairKey{idx[x], idx[y], k.day}
+				o := pairs[pk]
+				if o == nil {
+					o = &overlap{weeks: map[int]bool{}, sections: map[int]bool{}}
+					pairs[pk] = o
+				}
+				o.weeks[k.week] = true
+				o.sections[k.section] = true
+			}
+		}
+	}
+
+	conflicts := make([]Conflict, 0, len(pairs))
+	for pk, o := range pairs {
+		c := Conflict{A: pk.a, B: pk.b, Day: pk.day, Begin: 1 << 30}
+		for w := range o.weeks {
+			c.Weeks = append(c.Weeks, w)
+		}
+		sort.Ints(c.Weeks)
+		for sec := range o.sections {
+			if sec < c.Begin {
+				c.Begin = sec
+			}
+			if sec > c.End {
+				c.End = sec
+			}
+		}
+		conflicts = append(conflicts, c)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		a, b := conflicts[i], conflicts[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Begin != b.Begin {
+			return a.Begin < b.Begin
+		}
+		if a.A != b.A {
+			return a.A < b.A
+		}
+		return a.B < b.B
+	})
+	return conflicts
+}
+
+// Describe 返回可读的中文描述，如 "周三 3-4节（第1-8周）: 高等数学 与 大学物理"
+func (c Conflict) Describe(courses []Course) string {
+	a, b := courses[c.A], courses[c.B]
+	what := fmt.Sprintf("%s 与 %s", a.Name, b.Name)
+	if a.Name == b.Name {
+		what = fmt.Sprintf("%s 存在重复记录", a.Name)
+	}
+	sections := fmt.Sprintf("%d-%d节", c.Begin, c.End)
+	if c.Begin == c.End {
+		sections = fmt.Sprintf("第%d节", c.Begin)
+	}
+	return fmt.Sprintf("%s %s（第%s周）: %s", WeekdayName(c.Day), sections, FormatWeeks(c.Weeks), what)
+}
@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		courses []Course
+		want    []Conflict
+	}{
+		{
+			name: "没有冲突",
+			courses: []Course{
+				course("高数", "1", "1", "2", "1-16", "3-101"),
+				course("英语", "1", "3", "4", "1-16", "1-101"),
+				course("物理", "2", "1", "2", "1-16", "2-101"),
+			},
+		},
+		{
+			name: "部分节次、部分周次重叠",
+			courses: []Course{
+				course("高数", "3", "3", "5", "1-8", "3-101"),
+				course("物理", "3", "4", "6", "5-12", "2-101"),
+			},
+			want: []Conflict{{A: 0, B: 1, Day: 3, Begin: 4, End: 5, Weeks: []int{5, 6, 7, 8}}},
+		},
+		{
+			name: "单双周不冲突",
+			courses: []Course{
+				course("高数", "2", "1", "2", "1-15单", "3-101"),
+				course("物理", "2", "1", "2", "2-16双", "2-101"),
+			},
+		},
+		{
+			name: "重复记录",
+			courses: []Course{
+				course("高数", "1", "1", "2", "1-2", "3-101"),
+				course("高数", "1", "1", "2", "1-2", "3-101"),
+			},
+			want: []Conflict{{A: 0, B: 1, Day: 1, Begin: 1, End: 2, Weeks: []int{1, 2}}},
+		},
+		{
+			name: "三门课两两冲突，按星期和节次排序",
+			courses: []Course{
+				course("体育", "5", "7", "8", "1", "操场"),
+				course("高数", "1", "1", "2", "1", "3-101"),
+				course("英语", "1", "2", "3", "1", "1-101"),
+				course("物理", "1", "2", "2", "1", "2-101"),
+			},
+			want: []Conflict{
+				{A: 1, B: 2, Day: 1, Begin: 2, End: 2, Weeks: []int{1}},
+				{A: 1, B: 3, Day: 1, Begin: 2, End: 2, Weeks: []int{1}},
+				{A: 2, B: 3, Day: 1, Begin: 2, End: 2, Weeks: []int{1}},
+			},
+		},
+		{
+			name: "无法解析的记录不参与检查",
+			courses: []Course{
+				course("实践", "无", "无", "无", "无", "无"),
+				course("高数", "1", "1", "2", "全学期", "3-101"),
+				course("英语", "1", "1", "2", "1-16", "1-101"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := FindConflicts(tt.courses)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %+v\n want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConflictDescribe(t *testing.T) {
+	courses := []Course{
+		course("高数", "3", "3", "4", "1-8", "3-101"),
+		course("物理", "3", "4", "4", "1-8", "2-101"),
+		course("高数", "3", "3", "4", "1-8", "3-101"),
+	}
+	tests := []struct {
+		c    Conflict
+		want string
+	}{
+		{Conflict{A: 0, B: 1, Day: 3, Begin: 4, End: 4, Weeks: []int{1, 2, 3}}, "周三 第4节（第1-3周）: 高数 与 物理"},
+		{Conflict{A: 0, B: 2, Day: 3, Begin: 3, End: 4, Weeks: []int{1, 3}}, "周三 3-4节（第1,3周）: 高数 存在重复记录"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Describe(courses); got != tt.want {
+			t.Errorf("Describe(%+v) = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind 课表变更类型
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Arrangement 一组周次相同的上课安排
+type Arrangement struct {
+	Name         string `json:"name"`
+	DayOfWeek    string `json:"dayOfWeek"`
+	BeginSection string `json:"beginSection"`
+	EndSection   string `json:"endSection"`
+	Teacher      string `json:"teacher"`
+	Location     string `json:"location"`
+	Weeks        string `json:"weeks"`
+}
+
+// Change 一条课表变更
+// 新增只有 New，删除只有 Old，调整两者都有且周次相同
+type Change struct {
+	Kind ChangeKind   `json:"kind"`
+	Old  *Arrangement `json:"old,omitempty"`
+	New  *Arrangement `json:"new,omitempty"`
+}
+
+// slotKey 一次上课（不含日期），用于逐周比较
+type slotKey struct {
+	group groupKey
+	week  int
+}
+
+// groupKey 除周次以外的全部字段
+type groupKey struct {
+	name, day, begin, end, teacher, location string
+}
+
+func keyOf(c Course) groupKey {
+	return groupKey{c.Name, c.DayOfWeek, c.BeginSection, c.EndSection, c.Teacher, c.Location}
+}
+
+// Diff 按单次上课比较两个版本的课表
+// 同一门课仅部分周次调课时，只报告受影响的周次
+func Diff(old, new []Course) []Change {
+	oldSlots := expandSlots(old)
+	newSlots := expandSlots(new)
+
+	removed := make(map[groupKey][]int)
+	added := make(map[groupKey][]int)
+	for k, n := range oldSlots {
+		if n > newSlots[k] {
+			removed[k.group] = append(removed[k.group], k.week)
+		}
+	}
+	for k, n := range newSlots {
+		if n > oldSlots[k] {
+			added[k.group] = append(added[k.group], k.week)
+		}
+	}
+
+	var changes []Change
+	for _, ok := range sortedGroups(removed) {
+		weeks := removed[ok]
+		// 同名且周次完全一致的删除/新增视为一次调整
+		var match *groupKey
+		for _, nk := range sortedGroups(added) {
+			if nk.name == ok.name && sameWeeks(weeks, added[nk]) {
+				nk := nk
+				match = &nk
+				break
+			}
+		}
+		if match != nil {
+			changes = append(changes, Change{
+				Kind: Changed,
+				Old:  arrangementOf(ok, weeks),
+				New:  arrangementOf(*match, added[*match]),
+			})
+			delete(added, *match)
+			continue
+		}
+		changes = append(changes, Change{Kind: Removed, Old: arrangementOf(ok, weeks)})
+	}
+	for _, nk := range sortedGroups(added) {
+		changes = append(changes, Change{Kind: Added, New: arrangementOf(nk, added[nk])})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].primary().less(changes[j].primary())
+	})
+	return changes
+}
+
+// expandSlots 展开为逐周的上课记录；周数无法识别的课程按整条记录比较（week 为 0）
+func expandSlots(courses []Course) map[slotKey]int {
+	slots := make(map[slotKey]int)
+	for _, c := range courses {
+		weeks, err := ParseWeeks(c.Weeks)
+		if err != nil {
+			weeks = []int{0}
+		}
+		for _, w := range weeks {
+			slots[slotKey{keyOf(c), w}]++
+		}
+	}
+	return slots
+}
+
+func sortedGroups(m map[groupKey][]int) []groupKey {
+	keys := make([]groupKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if a.day != b.day {
+			return a.day < b.day
+		}
+		if a.begin != b.begin {
+			return a.begin < b.begin
+		}
+		return a.location < b.location
+	})
+	return keys
+}
+
+func sameWeeks(a, b []int) bool {
+	return FormatWeeks(a) == FormatWeeks(b)
+}
+
+func arrangementOf(k groupKey, weeks []int) *Arrangement {
+	w := FormatWeeks(weeks)
+	if w == "0" {
+		w = "无"
+	}
+	return &Arrangement{
+		Name: k.name, DayOfWeek: k.day, BeginSection: k.begin, EndSection: k.end,
+		Teacher: k.teacher, Location: k.location, Weeks: w,
+	}
+}
+
+func (c Change) primary() *Arrangement {
+	if c.Old != nil {
+		return c.Old
+	}
+	return c.New
+}
+
+func (a *Arrangement) less(b *Arrangement) bool {
+	ad, _ := strconv.Atoi(a.DayOfWeek)
+	bd, _ := strconv.Atoi(b.DayOfWeek)
+	if ad != bd {
+		return ad < bd
+	}
+	ab, _ := strconv.Atoi(a.BeginSection)
+	bb, _ := strconv.Atoi(b.BeginSection)
+	if ab != bb {
+		return ab < bb
+	}
+	return a.Name < b.Name
+}
+
+// when 返回 "周三 3-4节" 形式的时间描述
+func (a *Arrangement) when() string {
+	day, err := strconv.Atoi(a.DayOfWeek)
+	name := a.DayOfWeek
+	if err == nil {
+		name = WeekdayName(day)
+	}
+	return fmt.Sprintf("%s %s-%s节", name, a.BeginSection, a.EndSection)
+}
+
+// String 返回可读的中文描述，如 "周三 3-4节 高等数学 地点 3-101 → 5-203（第9-16周）"
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		a := c.New
+		return fmt.Sprintf("新增 %s %s @%s %s（第%s周）", a.when(), a.Name, a.Location, a.Teacher, a.Weeks)
+	case Removed:
+		a := c.Old
+		return fmt.Sprintf("取消 %s %s @%s %s（第%s周）", a.when(), a.Name, a.Location, a.Teacher, a.Weeks)
+	}
+
+	o, n := c.Old, c.New
+	prefix := o.when() + " " + o.Name
+	var diffs []string
+	if o.when() != n.when() {
+		prefix = o.Name
+		diffs = append(diffs, fmt.Sprintf("时间 %s → %s", o.when(), n.when()))
+	}
+	if o.Location != n.Location {
+		diffs = append(diffs, fmt.Sprintf("地点 %s → %s", o.Location, n.Location))
+	}
+	if o.Teacher != n.Teacher {
+		diffs = append(diffs, fmt.Sprintf("老师 %s → %s", o.Teacher, n.Teacher))
+	}
+	return fmt.Sprintf("%s %s（第%s周）", prefix, strings.Join(diffs, "，"), o.Weeks)
+}
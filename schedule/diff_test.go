@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	base := course("高数", "3", "3", "4", "1-16", "3-101")
+	tests := []struct {
+		name string
+		old  []Course
+		new  []Course
+		want []string
+	}{
+		{
+			name: "没有变化",
+			old:  []Course{base},
+			new:  []Course{base},
+		},
+		{
+			name: "周数写法不同视为相同",
+			old:  []Course{course("高数", "3", "3", "4", "1-4", "3-101")},
+			new:  []Course{course("高数", "3", "3", "4", "1,2,3-4", "3-101")},
+		},
+		{
+			name: "新增",
+			old:  []Course{base},
+			new:  []Course{base, course("英语", "1", "1", "2", "1-8", "1-101")},
+			want: []string{"added 英语 1-8"},
+		},
+		{
+			name: "取消",
+			old:  []Course{base, course("英语", "1", "1", "2", "1-8", "1-101")},
+			new:  []Course{base},
+			want: []string{"removed 英语 1-8"},
+		},
+		{
+			name: "整学期换教室",
+			old:  []Course{base},
+			new:  []Course{course("高数", "3", "3", "4", "1-16", "5-203")},
+			want: []string{"changed 高数 1-16 3-101→5-203"},
+		},
+		{
+			name: "部分周次调课，只报告受影响的周",
+			old:  []Course{base},
+			new: []Course{
+				course("高数", "3", "3", "4", "1-8", "3-101"),
+				course("高数", "3", "3", "4", "9-16", "5-203"),
+			},
+			want: []string{"changed 高数 9-16 3-101→5-203"},
+		},
+		{
+			name: "部分周次停课",
+			old:  []Course{base},
+			new:  []Course{course("高数", "3", "3", "4", "1-7,9-16", "3-101")},
+			want: []string{"removed 高数 8"},
+		},
+		{
+			name: "周次不一致时分别报告删除和新增",
+			old:  []Course{base},
+			new: []Course{
+				course("高数", "3", "3", "4", "1-8", "3-101"),
+				course("高数", "5", "1", "2", "9-12", "3-101"),
+			},
+			want: []string{"removed 高数 9-16", "added 高数 9-12"},
+		},
+		{
+			name: "周数无法识别时按整条记录比较",
+			old:  []Course{course("实践", "无", "无", "无", "无", "无")},
+			new:  nil,
+			want: []string{"removed 实践 无"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range Diff(tt.old, tt.new) {
+			got = append(got, describeChange(c))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// describeChange 测试用的简短描述：类型 课程 周数 [原地点→新地点]
+func describeChange(c Change) string {
+	a := c.primary()
+	s := string(c.Kind) + " " + a.Name + " " + a.Weeks
+	if c.Kind == Changed {
+		s += " " + c.Old.Location + "→" + c.New.Location
+	}
+	return s
+}
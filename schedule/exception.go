@@ -0,0 +1,237 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExceptionKind 单次课程调整的类型
+type ExceptionKind string
+
+const (
+	Cancel ExceptionKind = "cancel" // 停课
+	Move   ExceptionKind = "move"   // 调到其他时间或教室
+	Extra  ExceptionKind = "extra"  // 补课或加课
+)
+
+// Exception 教务系统中不会出现的单次调整，按课程名和日期定位
+// Section 为原课程所在的任一节次，同一天有两次同名课程时用于区分，0 表示不限
+// 调课时 ToDate、ToBegin、Location 为空表示沿用原值
+type Exception struct {
+	ID       int           `json:"id"`
+	Kind     ExceptionKind `json:"kind"`
+	Course   string        `json:"course"`
+	Date     string        `json:"date"`
+	Section  int           `json:"section,omitempty"`
+	ToDate   string        `json:"toDate,omitempty"`
+	ToBegin  int           `json:"toBegin,omitempty"`
+	ToEnd    int           `json:"toEnd,omitempty"`
+	Location string        `json:"location,omitempty"`
+	Note     string        `json:"note,omitempty"`
+}
+
+// String 返回可读描述，如 "#3 停课 高等数学 2025-10-15"
+func (e Exception) String() string {
+	s := fmt.Sprintf("#%d %s %s %s", e.ID, e.Kind.Label(), e.Course, e.Date)
+	if e.Section > 0 {
+		s += fmt.Sprintf(" 第%d节", e.Section)
+	}
+	var target []string
+	if e.ToDate != "" {
+		target = append(target, e.ToDate)
+	}
+	if e.ToBegin > 0 {
+		target = append(target, fmt.Sprintf("%d-%d节", e.ToBegin, e.ToEnd))
+	}
+	if e.Location != "" {
+		target = append(target, "@"+e.Location)
+	}
+	if len(target) > 0 {
+		arrow := " → "
+		if e.Kind == Extra {
+			arrow = " "
+		}
+		s += arrow + strings.Join(target, " ")
+	}
+	if e.Note != "" {
+		s += "（" + e.Note + "）"
+	}
+	return s
+}
+
+// Label 返回类型的中文名称
+func (k ExceptionKind) Label() string {
+	switch k {
+	case Cancel:
+		return "停课"
+	case Move:
+		return "调课"
+	case Extra:
+		return "补课"
+	}
+	return string(k)
+}
+
+// Validate 检查调整是否完整，日期和节次是否有效
+func (e Exception) Validate() error {
+	if e.Course == "" {
+		return fmt.Errorf("需要指定课程名")
+	}
+	if _, err := ParseDate(e.Date); err != nil {
+		return err
+	}
+	if e.ToDate != "" {
+		if _, err := ParseDate(e.ToDate); err != nil {
+			return err
+		}
+	}
+	if e.ToBegin != 0 || e.ToEnd != 0 {
+		if e.ToBegin < 1 || e.ToEnd < e.ToBegin || e.ToEnd > len(SectionTimes) {
+			return fmt.Errorf("节次超出作息表范围: %d-%d", e.ToBegin, e.ToEnd)
+		}
+	}
+	switch e.Kind {
+	case Cancel:
+	case Move:
+		if e.ToDate == "" && e.ToBegin == 0 && e.Location == "" {
+			return fmt.Errorf("调课需要指定新的日期、节次或地点")
+		}
+	case Extra:
+		if e.ToBegin == 0 {
+			return fmt.Errorf("补课需要指定节次")
+		}
+	default:
+		return fmt.Errorf("未知的调整类型 %q", e.Kind)
+	}
+	return nil
+}
+
+// ParseSections 解析 "3-4" 或 "5" 这样的节次范围
+func ParseSections(s string) (int, int, error) {
+	begin, end, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		end = begin
+	}
+	b, errB := strconv.Atoi(strings.TrimSpace(begin))
+	e, errE := strconv.Atoi(strings.TrimSpace(end))
+	if errB != nil || errE != nil || b < 1 || e < b || e > len(SectionTimes) {
+		return 0, 0, fmt.Errorf("无效的节次 %q", s)
+	}
+	return b, e, nil
+}
+
+// matches 判断调整是否作用于这次课
+func (e Exception) matches(s Session) bool {
+	return e.Kind != Extra && e.Course == s.Course.Name &&
+		e.Date == s.Start.In(TZ).Format("2006-01-02") &&
+		s.Covers(e.Section)
+}
+
+// applyExceptions 对展开后的上课安排应用停课、调课和补课，结果按开始时间排序
+func applyExceptions(sessions []Session, courses []Course, termStart time.Time, exceptions []Exception) []Session {
+	if len(exceptions) == 0 {
+		return sessions
+	}
+	out := sessions[:0:0]
+	for _, s := range sessions {
+		keep := true
+		for _, e := range exceptions {
+			if !e.matches(s) {
+				continue
+			}
+			if e.Kind == Cancel {
+				keep = false
+				break
+			}
+			moved, err := e.move(s, termStart)
+			if err == nil {
+				s = moved
+			}
+		}
+		if keep {
+			out = append(out, s)
+		}
+	}
+
+	for _, e := range exceptions {
+		if e.Kind != Extra {
+			continue
+		}
+		if s, err := e.extra(courses, termStart); err == nil {
+			out = append(out, s)
+		}
+	}
+	sortSessions(out)
+	return out
+}
+
+// move 返回调课后的上课安排
+func (e Exception) move(s Session, termStart time.Time) (Session, error) {
+	date := s.Start
+	if e.ToDate != "" {
+		d, err := ParseDate(e.ToDate)
+		if err != nil {
+			return s, err
+		}
+		date = d
+	}
+	begin, end := s.BeginSection, s.EndSection
+	if e.ToBegin > 0 {
+		begin, end = e.ToBegin, e.ToEnd
+	}
+	from, to, err := SectionSpan(date, begin, end)
+	if err != nil {
+		return s, err
+	}
+
+	note := fmt.Sprintf("调课（原 %s %s %d-%d节）", s.Start.In(TZ).Format("01-02"),
+		WeekdayName(Weekday(s.Start)), s.BeginSection, s.EndSection)
+	if e.ToDate == "" && e.ToBegin == 0 {
+		note = fmt.Sprintf("换教室（原 %s）", s.Location)
+	}
+	if e.Note != "" {
+		note += " " + e.Note
+	}
+
+	s.Start, s.End = from, to
+	s.Week = WeekOf(termStart, from)
+	s.BeginSection, s.EndSection = begin, end
+	if e.Location != "" {
+		s.Location = e.Location
+	}
+	s.Note = note
+	return s, nil
+}
+
+// extra 返回补课的上课安排，课程信息取自同名课程，没有同名课程时单独建立
+func (e Exception) extra(courses []Course, termStart time.Time) (Session, error) {
+	date, err := ParseDate(e.Date)
+	if err != nil {
+		return Session{}, err
+	}
+	from, to, err := SectionSpan(date, e.ToBegin, e.ToEnd)
+	if err != nil {
+		return Session{}, err
+	}
+	c := &Course{Name: e.Course, Location: e.Location}
+	for i := range courses {
+		if courses[i].Name == e.Course {
+			c = &courses[i]
+			break
+		}
+	}
+	loc := e.Location
+	if loc == "" {
+		loc = c.Location
+	}
+	note := "补课"
+	if e.Note != "" {
+		note += " " + e.Note
+	}
+	return Session{
+		Course: c, Week: WeekOf(termStart, from), Start: from, End: to,
+		BeginSection: e.ToBegin, EndSection: e.ToEnd, Location: loc, Note: note,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package schedule
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// termStart2025 2025-2026 学年第一学期第一周周一
+var termStart2025, _ = ParseDate("2025-09-08")
+
+// describeSessions 测试用的简短描述：日期 课程 节次 地点 [说明]
+func describeSessions(sessions []Session) []string {
+	var out []string
+	for _, s := range sessions {
+		d := fmt.Sprintf("%s %s %d-%d %s", s.Start.In(TZ).Format("2006-01-02"), s.Course.Name,
+			s.BeginSection, s.EndSection, s.Location)
+		if s.Note != "" {
+			d += " " + s.Note
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+func TestApplyExceptions(t *testing.T) {
+	// 第 1、2 周周三：2025-09-10、2025-09-17
+	math := course("高数", "3", "3", "4", "1-2", "3-101")
+	tests := []struct {
+		name       string
+		courses    []Course
+		exceptions []Exception
+		want       []string
+	}{
+		{
+			name:    "没有调整",
+			courses: []Course{math},
+			want:    []string{"2025-09-10 高数 3-4 3-101", "2025-09-17 高数 3-4 3-101"},
+		},
+		{
+			name:       "停课",
+			courses:    []Course{math},
+			exceptions: []Exception{{Kind: Cancel, Course: "高数", Date: "2025-09-10"}},
+			want:       []string{"2025-09-17 高数 3-4 3-101"},
+		},
+		{
+			name:       "日期不符不生效",
+			courses:    []Course{math},
+			exceptions: []Exception{{Kind: Cancel, Course: "高数", Date: "2025-09-11"}},
+			want:       []string{"2025-09-10 高数 3-4 3-101", "2025-09-17 高数 3-4 3-101"},
+		},
+		{
+			name:       "调到其他日期和节次",
+			courses:    []Course{math},
+			exceptions: []Exception{{Kind: Move, Course: "高数", Date: "2025-09-10", ToDate: "2025-09-12", ToBegin: 1, ToEnd: 2}},
+			want: []string{
+				"2025-09-12 高数 1-2 3-101 调课（原 09-10 周三 3-4节）",
+				"2025-09-17 高数 3-4 3-101",
+			},
+		},
+		{
+			name:       "只换教室",
+			courses:    []Course{math},
+			exceptions: []Exception{{Kind: Move, Course: "高数", Date: "2025-09-17", Location: "5-203"}},
+			want: []string{
+				"2025-09-10 高数 3-4 3-101",
+				"2025-09-17 高数 3-4 5-203 换教室（原 3-101）",
+			},
+		},
+		{
+			name:       "补课沿用同名课程的地点",
+			courses:    []Course{math},
+			exceptions: []Exception{{Kind: Extra, Course: "高数", Date: "2025-09-13", ToBegin: 5, ToEnd: 6, Note: "第一次"}},
+			want: []string{
+				"2025-09-10 高数 3-4 3-101",
+				"2025-09-13 高数 5-6 3-101 补课 第一次",
+				"2025-09-17 高数 3-4 3-101",
+			},
+		},
+		{
+			name:       "补课没有同名课程时单独建立",
+			courses:    []Course{math},
+			exceptions: []Exception{{Kind: Extra, Course: "讲座", Date: "2025-09-11", ToBegin: 12, ToEnd: 13, Location: "报告厅"}},
+			want: []string{
+				"2025-09-10 高数 3-4 3-101",
+				"2025-09-11 讲座 12-13 报告厅 补课",
+				"2025-09-17 高数 3-4 3-101",
+			},
+		},
+		{
+			// 覆盖规则改名后，调整按改名后的课程名匹配
+			name:    "按改名后的课程名匹配",
+			courses: []Course{course("高数（重修）", "3", "3", "4", "1", "3-101")},
+			exceptions: []Exception{
+				{Kind: Cancel, Course: "高数", Date: "2025-09-10"},
+				{Kind: Move, Course: "高数（重修）", Date: "2025-09-10", Location: "5-203"},
+			},
+			want: []string{"2025-09-10 高数（重修） 3-4 5-203 换教室（原 3-101）"},
+		},
+		{
+			name: "同一天两次同名课程按节次区分",
+			courses: []Course{
+				course("高数", "3", "1", "2", "1", "3-101"),
+				course("高数", "3", "7", "8", "1", "3-101"),
+			},
+			exceptions: []Exception{{Kind: Cancel, Course: "高数", Date: "2025-09-10", Section: 8}},
+			want:       []string{"2025-09-10 高数 1-2 3-101"},
+		},
+		{
+			// 相邻节次合并后，指定合并范围内的任一节都能匹配
+			name:       "节次落在合并后的范围内",
+			courses:    []Course{course("高数", "3", "1", "4", "1", "3-101")},
+			exceptions: []Exception{{Kind: Cancel, Course: "高数", Date: "2025-09-10", Section: 3}},
+		},
+	}
+	for _, tt := range tests {
+		sessions := Expand(tt.courses, termStart2025, Adjustments{Exceptions: tt.exceptions})
+		if got := describeSessions(sessions); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %q\n want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExceptionValidate(t *testing.T) {
+	valid := []Exception{
+		{Kind: Cancel, Course: "高数", Date: "2025-09-10"},
+		{Kind: Move, Course: "高数", Date: "2025-09-10", Location: "5-203"},
+		{Kind: Extra, Course: "高数", Date: "2025-09-13", ToBegin: 5, ToEnd: 6},
+	}
+	for _, e := range valid {
+		if err := e.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", e, err)
+		}
+	}
+
+	invalid := []Exception{
+		{Kind: Cancel, Date: "2025-09-10"},                                    // 没有课程名
+		{Kind: Cancel, Course: "高数", Date: "9/10"},                            // 日期格式
+		{Kind: Move, Course: "高数", Date: "2025-09-10"},                        // 没有调整内容
+		{Kind: Extra, Course: "高数", Date: "2025-09-13"},                       // 补课没有节次
+		{Kind: Extra, Course: "高数", Date: "2025-09-13", ToBegin: 5, ToEnd: 4}, // 节次颠倒
+		{Kind: Extra, Course: "高数", Date: "2025-09-13", ToBegin: 14, ToEnd: 15},
+		{Kind: "swap", Course: "高数", Date: "2025-09-10"},
+	}
+	for _, e := range invalid {
+		if err := e.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", e)
+		}
+	}
+}
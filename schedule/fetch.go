@@ -1,7 +1,9 @@
 package schedule
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +17,9 @@ const (
 	ScheduleURL    = BaseURL + "/jwapp/sys/homeapp/api/home/student/getMyScheduleDetail.do"
 )
 
+// ErrSessionExpired 登录状态已失效（请求被重定向到统一身份认证）
+var ErrSessionExpired = errors.New("登录已失效，请重新登录")
+
 // Fetcher 课表数据获取器
 type Fetcher struct {
 	Client *http.Client
@@ -36,9 +41,9 @@ func (f *Fetcher) FetchUserInfo() (*UserInfo, error) {
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, err
 	}
 
 	var result map[string]interface{}
@@ -70,8 +75,16 @@ func (f *Fetcher) FetchUserInfo() (*UserInfo, error) {
 	return info, nil
 }
 
+// ScheduleData 课表接口返回的原始记录
+type ScheduleData struct {
+	// Arranged 有固定上课时间的课程
+	Arranged []map[string]interface{}
+	// NotArranged 未安排时间的课程，如实践课、集中授课
+	NotArranged []map[string]interface{}
+}
+
 // FetchSchedule 获取指定学期的课表数据
-func (f *Fetcher) FetchSchedule(termCode, studentID string) ([]map[string]interface{}, error) {
+func (f *Fetcher) FetchSchedule(termCode, studentID string) (*ScheduleData, error) {
 	formData := url.Values{
 		"termCode":    {termCode},
 		"studentCode": {studentID},
@@ -87,23 +100,53 @@ func (f *Fetcher) FetchSchedule(termCode, studentID string) ([]map[string]interf
 	}
 	defer resp.Body.Close()
 
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseScheduleResponse(body)
+}
+
+// readBody 读取 jwapp 接口响应，会话失效时返回 ErrSessionExpired
+func readBody(resp *http.Response) ([]byte, error) {
+	if strings.Contains(resp.Request.URL.Path, "authserver") {
+		return nil, ErrSessionExpired
+	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("读取响应失败: %w", err)
 	}
+	// 未登录时部分接口直接返回登录页 HTML
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return nil, ErrSessionExpired
+	}
+	return body, nil
+}
 
+// notArrangedKeys 未安排课程列表可能使用的字段名
+var notArrangedKeys = []string{"notArrangeList", "notArrangedList", "unArrangedList", "noArrangeList"}
+
+// ParseScheduleResponse 从课表接口的响应体中提取课程记录
+func ParseScheduleResponse(body []byte) (*ScheduleData, error) {
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
 	}
 
-	var list []interface{}
+	var list, notArranged []interface{}
 	if datas, ok := result["datas"].(map[string]interface{}); ok {
 		if l, ok := datas["arrangedList"].([]interface{}); ok {
 			list = l
 		} else if l, ok := datas["list"].([]interface{}); ok {
 			list = l
 		}
+		for _, k := range notArrangedKeys {
+			if l, ok := datas[k].([]interface{}); ok {
+				notArranged = l
+				break
+			}
+		}
 	}
 	if list == nil {
 		if data, ok := result["data"].(map[string]interface{}); ok {
@@ -113,15 +156,18 @@ func (f *Fetcher) FetchSchedule(termCode, studentID string) ([]map[string]interf
 		}
 	}
 
-	if len(list) == 0 {
+	if len(list) == 0 && len(notArranged) == 0 {
 		return nil, fmt.Errorf("未获取到课程数据，请检查学期代码和学号")
 	}
+	return &ScheduleData{Arranged: toRecords(list), NotArranged: toRecords(notArranged)}, nil
+}
 
+func toRecords(list []interface{}) []map[string]interface{} {
 	items := make([]map[string]interface{}, 0, len(list))
 	for _, item := range list {
 		if m, ok := item.(map[string]interface{}); ok {
 			items = append(items, m)
 		}
 	}
-	return items, nil
+	return items
 }
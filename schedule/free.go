@@ -0,0 +1,130 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FreeSlot 所有人都没课的一段连续节次
+type FreeSlot struct {
+	Day   int `json:"day"`
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// Sections 空闲的节数
+func (f FreeSlot) Sections() int {
+	return f.End - f.Begin + 1
+}
+
+// Minutes 从第一节上课到最后一节下课的时长（分钟）
+func (f FreeSlot) Minutes() int {
+	return sessionMinutes(f.Begin, f.End)
+}
+
+// FreeFilter 空闲时间的限制条件，零值表示不限制
+type FreeFilter struct {
+	Days        []int // 只看这些星期几
+	After       int   // 只看第 After 节之后
+	Before      int   // 只看第 Before 节之前
+	MinSections int   // 至少连续空闲几节
+}
+
+// Validate 检查节次和星期是否在有效范围内
+func (f FreeFilter) Validate() error {
+	n := len(SectionTimes)
+	if f.After < 0 || f.After > n {
+		return fmt.Errorf("--after 应在 0-%d 之间: %d", n, f.After)
+	}
+	if f.Before < 0 || f.Before > n {
+		return fmt.Errorf("--before 应在 0-%d 之间: %d", n, f.Before)
+	}
+	for _, d := range f.Days {
+		if d < 1 || d > 7 {
+			return fmt.Errorf("星期应在 1-7 之间: %d", d)
+		}
+	}
+	return nil
+}
+
+// MaxWeek 返回课程中出现的最大周次，没有课程时为 0
+func MaxWeek(courses []Course) int {
+	max := 0
+	for _, c := range courses {
+		weeks, err := ParseWeeks(c.Weeks)
+		if err == nil && len(weeks) > 0 && weeks[len(weeks)-1] > max {
+			max = weeks[len(weeks)-1]
+		}
+	}
+	return max
+}
+
+// FindFreeSlots 找出多份课表在指定周次中都空闲的时间段
+// 某节只有在每一周都没人上课时才算空闲，结果按空闲时长从长到短排序
+func FindFreeSlots(timetables [][]Course, weeks []int, filter FreeFilter) []FreeSlot {
+	busy := make(map[slot]bool)
+	for _, courses := range timetables {
+		for _, c := range courses {
+			day, begin, end, err := c.Slot()
+			if err != nil {
+				continue
+			}
+			ws, err := ParseWeeks(c.Weeks)
+			if err != nil {
+				continue
+			}
+			for _, w := range ws {
+				for sec := begin; sec <= end; sec++ {
+					busy[slot{w, day, sec}] = true
+				}
+			}
+		}
+	}
+
+	days := filter.Days
+	if len(days) == 0 {
+		days = []int{1, 2, 3, 4, 5, 6, 7}
+	}
+	first, last := max(filter.After+1, 1), len(SectionTimes)
+	if filter.Before > 0 && filter.Before-1 < last {
+		last = filter.Before - 1
+	}
+
+	free := func(day, sec int) bool {
+		for _, w := range weeks {
+			if busy[slot{w, day, sec}] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var slots []FreeSlot
+	for _, day := range days {
+		for sec := first; sec <= last; sec++ {
+			if !free(day, sec) {
+				continue
+			}
+			s := FreeSlot{Day: day, Begin: sec, End: sec}
+			for s.End+1 <= last && free(day, s.End+1) {
+				s.End++
+			}
+			sec = s.End
+			if s.Sections() >= filter.MinSections {
+				slots = append(slots, s)
+			}
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		a, b := slots[i], slots[j]
+		if a.Minutes() != b.Minutes() {
+			return a.Minutes() > b.Minutes()
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return a.Begin < b.Begin
+	})
+	return slots
+}
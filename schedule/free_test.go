@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindFreeSlots(t *testing.T) {
+	alice := []Course{course("高数", "1", "1", "2", "1-16", "3-101"), course("物理", "1", "3", "4", "1-8", "2-101")}
+	bob := []Course{course("英语", "1", "5", "6", "1-16", "1-101")}
+	monday := []int{1}
+
+	tests := []struct {
+		name       string
+		timetables [][]Course
+		weeks      []int
+		filter     FreeFilter
+		want       []FreeSlot
+	}{
+		{
+			name:       "一份课表",
+			timetables: [][]Course{alice},
+			weeks:      []int{1},
+			filter:     FreeFilter{Days: monday},
+			want:       []FreeSlot{{1, 5, 14}},
+		},
+		{
+			// 3-4 节只在前 8 周有课
+			name:       "只看指定周次",
+			timetables: [][]Course{alice},
+			weeks:      []int{9, 10},
+			filter:     FreeFilter{Days: monday},
+			want:       []FreeSlot{{1, 3, 14}},
+		},
+		{
+			name:       "任一周有课都不算空闲",
+			timetables: [][]Course{alice},
+			weeks:      []int{8, 9},
+			filter:     FreeFilter{Days: monday},
+			want:       []FreeSlot{{1, 5, 14}},
+		},
+		{
+			// 3-4 节 95 分钟，7-8 节 100 分钟
+			name:       "多份课表取交集，按时长排序",
+			timetables: [][]Course{alice, bob},
+			weeks:      []int{9},
+			filter:     FreeFilter{Days: monday, Before: 9},
+			want:       []FreeSlot{{1, 7, 8}, {1, 3, 4}},
+		},
+		{
+			name:       "至少连续空闲的节数",
+			timetables: [][]Course{alice, bob},
+			weeks:      []int{9},
+			filter:     FreeFilter{Days: monday, MinSections: 3},
+			want:       []FreeSlot{{1, 7, 14}},
+		},
+		{
+			name:       "只看某节之后",
+			timetables: [][]Course{alice, bob},
+			weeks:      []int{1},
+			filter:     FreeFilter{Days: monday, After: 11},
+			want:       []FreeSlot{{1, 12, 14}},
+		},
+		{
+			name:       "多天按时长、星期排序",
+			timetables: [][]Course{alice},
+			weeks:      []int{1},
+			filter:     FreeFilter{Days: []int{2, 1}, After: 4, Before: 8},
+			want:       []FreeSlot{{1, 5, 7}, {2, 5, 7}},
+		},
+	}
+	for _, tt := range tests {
+		got := FindFreeSlots(tt.timetables, tt.weeks, tt.filter)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %+v\n want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFreeFilterValidate(t *testing.T) {
+	for _, f := range []FreeFilter{{}, {After: 14}, {Before: 14}, {Days: []int{1, 7}}} {
+		if err := f.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", f, err)
+		}
+	}
+	for _, f := range []FreeFilter{{After: -1}, {After: 15}, {Before: 15}, {Days: []int{0}}, {Days: []int{8}}} {
+		if err := f.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", f)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package schedule
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxMergeGap 相邻两节之间的课间不超过该分钟数时才合并（午休、晚饭不合并）
+const maxMergeGap = 20
+
+// CourseEntity 一个教学班及其全部上课时间
+type CourseEntity struct {
+	Name     string     `json:"name"`
+	Code     string     `json:"code,omitempty"`
+	ClassID  string     `json:"classId,omitempty"`
+	Teacher  string     `json:"teacher"`
+	Credits  float64    `json:"credits,omitempty"`
+	Slots    []TimeSlot `json:"slots"`
+	Sessions int        `json:"sessions"`
+}
+
+// TimeSlot 教学班的一个固定上课时间
+type TimeSlot struct {
+	Day      int    `json:"day"`
+	Begin    int    `json:"begin"`
+	End      int    `json:"end"`
+	Weeks    string `json:"weeks"`
+	Location string `json:"location"`
+	Place    Place  `json:"place"`
+}
+
+// entityKey 教学班标识：有教学班 ID 时使用 ID，否则按课程名和教师区分
+func entityKey(c Course) string {
+	if c.ClassID != "" {
+		return "id:" + c.ClassID
+	}
+	return c.Name + "\x00" + c.Teacher
+}
+
+// GroupCourses 将课程记录按教学班归并，按首次出现的顺序返回
+// 地点按 table 解析，table 为空时使用默认别名表
+func GroupCourses(courses []Course, table *LocationTable) []CourseEntity {
+	if table == nil {
+		table = DefaultLocationTable()
+	}
+	index := make(map[string]int)
+	var entities []CourseEntity
+	for _, c := range courses {
+		key := entityKey(c)
+		i, ok := index[key]
+		if !ok {
+			i = len(entities)
+			index[key] = i
+			entities = append(entities, CourseEntity{
+				Name: c.Name, Code: c.Code, ClassID: c.ClassID, Teacher: c.Teacher, Credits: c.Credits,
+			})
+		}
+		day, begin, end, err := c.Slot()
+		if err != nil {
+			continue
+		}
+		e := &entities[i]
+		e.Slots = append(e.Slots, TimeSlot{
+			Day: day, Begin: begin, End: end, Weeks: c.Weeks,
+			Location: c.Location, Place: ParseLocation(c.Location, table),
+		})
+		if weeks, err := ParseWeeks(c.Weeks); err == nil {
+			e.Sessions += len(weeks)
+		}
+	}
+	for i := range entities {
+		slots := entities[i].Slots
+		sort.SliceStable(slots, func(a, b int) bool {
+			if slots[a].Day != slots[b].Day {
+				return slots[a].Day < slots[b].Day
+			}
+			return slots[a].Begin < slots[b].Begin
+		})
+	}
+	return entities
+}
+
+// MergeAdjacent 合并同一教学班在同一天、周次和地点都相同的相邻节次，如 3-4 节与 5 节合并为 3-5 节
+// 无法解析的记录原样保留，结果保持原有顺序
+func MergeAdjacent(courses []Course) []Course {
+	type block struct {
+		idx        int
+		begin, end int
+		key        string
+	}
+	var blocks []block
+	for i, c := range courses {
+		_, begin, end, err := c.Slot()
+		if err != nil {
+			continue
+		}
+		weeks, err := ParseWeeks(c.Weeks)
+		if err != nil {
+			continue
+		}
+		key := strings.Join([]string{entityKey(c), c.DayOfWeek, FormatWeeks(weeks), c.Location}, "\x00")
+		blocks = append(blocks, block{idx: i, begin: begin, end: end, key: key})
+	}
+	sort.SliceStable(blocks, func(a, b int) bool {
+		if blocks[a].key != blocks[b].key {
+			return blocks[a].key < blocks[b].key
+		}
+		return blocks[a].begin < blocks[b].begin
+	})
+
+	// absorbed[i] 为 true 表示该记录已并入前一段
+	absorbed := make(map[int]bool)
+	ends := make(map[int]int)
+	for i := 0; i < len(blocks); {
+		head := blocks[i]
+		end := head.end
+		j := i + 1
+		for ; j < len(blocks) && blocks[j].key == head.key && adjacent(end, blocks[j].begin); j++ {
+			end = blocks[j].end
+			absorbed[blocks[j].idx] = true
+		}
+		if end != head.end {
+			ends[head.idx] = end
+		}
+		i = j
+	}
+
+	out := make([]Course, 0, len(courses)-len(absorbed))
+	for i, c := range courses {
+		if absorbed[i] {
+			continue
+		}
+		if end, ok := ends[i]; ok {
+			c.EndSection = strconv.Itoa(end)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// adjacent 判断第 next 节是否紧接在第 end 节之后且课间足够短
+// 重叠或重复的记录不算相邻，留给冲突检查报告
+func adjacent(end, next int) bool {
+	if next != end+1 || next > len(SectionTimes) || end < 1 {
+		return false
+	}
+	gap := clockMinutes(SectionTimes[next-1].Begin) - clockMinutes(SectionTimes[end-1].End)
+	return gap <= maxMergeGap
+}
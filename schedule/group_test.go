@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+// course 构造测试用课程，星期、节次、周数和地点之外的字段取固定值
+func course(name, day, begin, end, weeks, location string) Course {
+	return Course{
+		Name: name, DayOfWeek: day, BeginSection: begin, EndSection: end,
+		Teacher: "张三", Location: location, Weeks: weeks,
+	}
+}
+
+func TestMergeAdjacent(t *testing.T) {
+	type slot struct{ name, begin, end string }
+	tests := []struct {
+		name string
+		in   []Course
+		want []slot
+	}{
+		{
+			name: "连续两段合并",
+			in:   []Course{course("高数", "1", "1", "2", "1-16", "3-101"), course("高数", "1", "3", "4", "1-16", "3-101")},
+			want: []slot{{"高数", "1", "4"}},
+		},
+		{
+			name: "三段合并，记录顺序打乱",
+			in: []Course{
+				course("高数", "1", "5", "5", "1-16", "3-101"),
+				course("高数", "1", "1", "2", "1-16", "3-101"),
+				course("高数", "1", "3", "4", "1-16", "3-101"),
+			},
+			want: []slot{{"高数", "1", "5"}},
+		},
+		{
+			name: "午休（第5、6节之间）不合并",
+			in:   []Course{course("高数", "1", "4", "5", "1-16", "3-101"), course("高数", "1", "6", "7", "1-16", "3-101")},
+			want: []slot{{"高数", "4", "5"}, {"高数", "6", "7"}},
+		},
+		{
+			name: "晚饭（第11、12节之间）不合并",
+			in:   []Course{course("高数", "1", "10", "11", "1-16", "3-101"), course("高数", "1", "12", "13", "1-16", "3-101")},
+			want: []slot{{"高数", "10", "11"}, {"高数", "12", "13"}},
+		},
+		{
+			name: "重叠的记录不合并，留给冲突检查",
+			in:   []Course{course("高数", "1", "1", "3", "1-16", "3-101"), course("高数", "1", "3", "4", "1-16", "3-101")},
+			want: []slot{{"高数", "1", "3"}, {"高数", "3", "4"}},
+		},
+		{
+			name: "重复的记录不合并",
+			in:   []Course{course("高数", "1", "1", "2", "1-16", "3-101"), course("高数", "1", "1", "2", "1-16", "3-101")},
+			want: []slot{{"高数", "1", "2"}, {"高数", "1", "2"}},
+		},
+		{
+			name: "周数不同不合并",
+			in:   []Course{course("高数", "1", "1", "2", "1-8", "3-101"), course("高数", "1", "3", "4", "9-16", "3-101")},
+			want: []slot{{"高数", "1", "2"}, {"高数", "3", "4"}},
+		},
+		{
+			name: "周数写法不同但相同时合并",
+			in:   []Course{course("高数", "1", "1", "2", "1-3,5", "3-101"), course("高数", "1", "3", "4", "1,2,3,5", "3-101")},
+			want: []slot{{"高数", "1", "4"}},
+		},
+		{
+			name: "地点不同不合并",
+			in:   []Course{course("高数", "1", "1", "2", "1-16", "3-101"), course("高数", "1", "3", "4", "1-16", "3-102")},
+			want: []slot{{"高数", "1", "2"}, {"高数", "3", "4"}},
+		},
+		{
+			name: "不同课程、不同星期不合并",
+			in: []Course{
+				course("高数", "1", "1", "2", "1-16", "3-101"),
+				course("英语", "1", "3", "4", "1-16", "3-101"),
+				course("高数", "2", "3", "4", "1-16", "3-101"),
+			},
+			want: []slot{{"高数", "1", "2"}, {"英语", "3", "4"}, {"高数", "3", "4"}},
+		},
+		{
+			name: "无法解析的记录原样保留",
+			in:   []Course{course("实践", "无", "无", "无", "无", "无"), course("高数", "1", "1", "2", "1-16", "3-101")},
+			want: []slot{{"实践", "无", "无"}, {"高数", "1", "2"}},
+		},
+	}
+	for _, tt := range tests {
+		var got []slot
+		for _, c := range MergeAdjacent(tt.in) {
+			got = append(got, slot{c.Name, c.BeginSection, c.EndSection})
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMergeAdjacentKeepsRecord(t *testing.T) {
+	a := course("高数", "1", "1", "2", "1-16", "3-101")
+	a.Record = 1
+	b := course("高数", "1", "1", "2", "1-16", "3-101")
+	b.Record = 2
+	got := MergeAdjacent([]Course{a, b})
+	if len(got) != 2 || got[0].Record != 1 || got[1].Record != 2 {
+		t.Fatalf("got %+v, want both records kept", got)
+	}
+	if conflicts := FindConflicts(got); len(conflicts) != 1 {
+		t.Errorf("FindConflicts = %+v, want the duplicate reported", conflicts)
+	}
+}
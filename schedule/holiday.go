@@ -0,0 +1,226 @@
+package schedule
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+//go:embed holidays/*.json
+var bundledHolidays embed.FS
+
+// Holiday 一段放假日期（含首尾两天）
+type Holiday struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Workday 调休上班日，Follows 为当天按哪一天的课表上课（通常是被调走的那个工作日）
+type Workday struct {
+	Date    string `json:"date"`
+	Follows string `json:"follows,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+// HolidayYear 一年的放假和调休安排
+type HolidayYear struct {
+	Year     int       `json:"year"`
+	Holidays []Holiday `json:"holidays"`
+	Workdays []Workday `json:"workdays"`
+}
+
+// Calendar 节假日日历，按年份保存
+type Calendar struct {
+	years map[int]HolidayYear
+}
+
+// BundledCalendar 返回程序内置的节假日数据
+func BundledCalendar() *Calendar {
+	c := &Calendar{years: make(map[int]HolidayYear)}
+	files, _ := bundledHolidays.ReadDir("holidays")
+	for _, f := range files {
+		data, err := bundledHolidays.ReadFile("holidays/" + f.Name())
+		if err != nil {
+			continue
+		}
+		var y HolidayYear
+		if json.Unmarshal(data, &y) == nil {
+			c.years[y.Year] = y
+		}
+	}
+	return c
+}
+
+// LoadCalendarFile 读取用户的节假日文件，其中出现的年份整体替换内置数据
+// 文件内容为单个年份对象或年份对象数组
+func (c *Calendar) LoadCalendarFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取节假日文件失败: %w", err)
+	}
+	var years []HolidayYear
+	if err := json.Unmarshal(data, &years); err != nil {
+		var y HolidayYear
+		if err := json.Unmarshal(data, &y); err != nil {
+			return fmt.Errorf("解析节假日文件 %s 失败: %w", path, err)
+		}
+		years = []HolidayYear{y}
+	}
+	for _, y := range years {
+		if err := y.validate(); err != nil {
+			return fmt.Errorf("节假日文件 %s: %w", path, err)
+		}
+		c.years[y.Year] = y
+	}
+	return nil
+}
+
+func (y HolidayYear) validate() error {
+	if y.Year == 0 {
+		return fmt.Errorf("缺少 year")
+	}
+	for _, h := range y.Holidays {
+		from, err := ParseDate(h.From)
+		if err != nil {
+			return fmt.Errorf("%s: %w", h.Name, err)
+		}
+		to, err := ParseDate(h.To)
+		if err != nil {
+			return fmt.Errorf("%s: %w", h.Name, err)
+		}
+		if to.Before(from) {
+			return fmt.Errorf("%s: 结束日期早于开始日期", h.Name)
+		}
+	}
+	for _, w := range y.Workdays {
+		if _, err := ParseDate(w.Date); err != nil {
+			return err
+		}
+		if w.Follows != "" {
+			if _, err := ParseDate(w.Follows); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// HolidayOn 返回某天所在的假期
+func (c *Calendar) HolidayOn(date time.Time) (Holiday, bool) {
+	if c == nil {
+		return Holiday{}, false
+	}
+	day := date.In(TZ).Format("2006-01-02")
+	y := c.years[date.In(TZ).Year()]
+	for _, h := range y.Holidays {
+		if day >= h.From && day <= h.To {
+			return h, true
+		}
+	}
+	// 跨年的假期记录在前一年
+	for _, h := range c.years[date.In(TZ).Year()-1].Holidays {
+		if day >= h.From && day <= h.To {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// WorkdayOn 返回某天的调休安排
+func (c *Calendar) WorkdayOn(date time.Time) (Workday, bool) {
+	if c == nil {
+		return Workday{}, false
+	}
+	day := date.In(TZ).Format("2006-01-02")
+	for _, w := range c.years[date.In(TZ).Year()].Workdays {
+		if w.Date == day {
+			return w, true
+		}
+	}
+	return Workday{}, false
+}
+
+// HolidaysBetween 返回与 [from, to) 有交集的假期，按开始日期排序
+func (c *Calendar) HolidaysBetween(from, to time.Time) []Holiday {
+	if c == nil {
+		return nil
+	}
+	lo, hi := from.In(TZ).Format("2006-01-02"), to.In(TZ).Format("2006-01-02")
+	var out []Holiday
+	for _, y := range c.years {
+		for _, h := range y.Holidays {
+			if h.To >= lo && h.From < hi {
+				out = append(out, h)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].From < out[j].From })
+	return out
+}
+
+// WorkdaysBetween 返回 [from, to) 内的调休上班日，按日期排序
+func (c *Calendar) WorkdaysBetween(from, to time.Time) []Workday {
+	if c == nil {
+		return nil
+	}
+	lo, hi := from.In(TZ).Format("2006-01-02"), to.In(TZ).Format("2006-01-02")
+	var out []Workday
+	for _, y := range c.years {
+		for _, w := range y.Workdays {
+			if w.Date >= lo && w.Date < hi {
+				out = append(out, w)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// applyHolidays 去掉假期中的课，并把被调走那天的课移到调休上班日
+func applyHolidays(sessions []Session, termStart time.Time, cal *Calendar) []Session {
+	if cal == nil || len(sessions) == 0 {
+		return sessions
+	}
+	// 被调走的日期 → 调休上班日
+	moved := make(map[string]Workday)
+	last := sessions[len(sessions)-1].Start
+	for _, w := range cal.WorkdaysBetween(termStart.AddDate(0, 0, -7), last.AddDate(0, 0, 7)) {
+		if w.Follows != "" {
+			moved[w.Follows] = w
+		}
+	}
+
+	out := sessions[:0:0]
+	for _, s := range sessions {
+		day := s.Start.In(TZ).Format("2006-01-02")
+		if w, ok := moved[day]; ok {
+			date, _ := ParseDate(w.Date)
+			from, to, err := SectionSpan(date, s.BeginSection, s.EndSection)
+			if err != nil {
+				continue
+			}
+			s.Note = fmt.Sprintf("%s（按 %s %s 课表上课）", workdayName(w), s.Start.In(TZ).Format("01-02"), WeekdayName(Weekday(s.Start)))
+			s.Start, s.End = from, to
+			s.Week = WeekOf(termStart, from)
+			out = append(out, s)
+			continue
+		}
+		if _, ok := cal.HolidayOn(s.Start); ok {
+			continue
+		}
+		out = append(out, s)
+	}
+	sortSessions(out)
+	return out
+}
+
+func workdayName(w Workday) string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return "调休"
+}
@@ -0,0 +1,83 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyHolidays(t *testing.T) {
+	// 2025 年国庆节、中秋节 10-01 至 10-08 放假，跨第 4、5 周
+	// 09-28（周日）按 10-07（周二）上课，10-11（周六）按 10-08（周三）上课
+	bundled := BundledCalendar()
+	tests := []struct {
+		name    string
+		courses []Course
+		cal     *Calendar
+		want    []string
+	}{
+		{
+			name:    "没有日历不调整",
+			courses: []Course{course("高数", "3", "3", "4", "4-5", "3-101")},
+			want:    []string{"2025-10-01 高数 3-4 3-101", "2025-10-08 高数 3-4 3-101"},
+		},
+		{
+			name:    "跨两周的假期只去掉假期内的课",
+			courses: []Course{course("英语", "1", "1", "2", "4-5", "1-101"), course("物理", "4", "1", "2", "4-5", "2-101")},
+			cal:     bundled,
+			want:    []string{"2025-09-29 英语 1-2 1-101", "2025-10-09 物理 1-2 2-101"},
+		},
+		{
+			name:    "调休上班日按被调走那天的课表上课",
+			courses: []Course{course("英语", "2", "1", "2", "3-5", "1-101")},
+			cal:     bundled,
+			want: []string{
+				"2025-09-23 英语 1-2 1-101",
+				"2025-09-28 英语 1-2 1-101 国庆节调休（按 10-07 周二 课表上课）",
+				"2025-09-30 英语 1-2 1-101",
+			},
+		},
+		{
+			name:    "调休上班日在假期之后",
+			courses: []Course{course("高数", "3", "3", "4", "4-5", "3-101")},
+			cal:     bundled,
+			want:    []string{"2025-10-11 高数 3-4 3-101 国庆节调休（按 10-08 周三 课表上课）"},
+		},
+		{
+			// 跨年的假期记录在前一年
+			name:    "跨年的假期",
+			courses: []Course{course("体育", "5", "7", "8", "17-18", "操场")},
+			cal: &Calendar{years: map[int]HolidayYear{2025: {
+				Year:     2025,
+				Holidays: []Holiday{{Name: "元旦", From: "2025-12-31", To: "2026-01-02"}},
+			}}},
+			want: []string{"2026-01-09 体育 7-8 操场"},
+		},
+		{
+			name:    "没有对应日期的调休日不搬课",
+			courses: []Course{course("英语", "2", "1", "2", "1", "1-101")},
+			cal: &Calendar{years: map[int]HolidayYear{2025: {
+				Year:     2025,
+				Workdays: []Workday{{Date: "2025-09-13"}},
+			}}},
+			want: []string{"2025-09-09 英语 1-2 1-101"},
+		},
+	}
+	for _, tt := range tests {
+		sessions := Expand(tt.courses, termStart2025, Adjustments{Calendar: tt.cal})
+		if got := describeSessions(sessions); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %q\n want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyHolidaysBeforeExceptions(t *testing.T) {
+	// 调休搬来的课仍可以再停课
+	sessions := Expand([]Course{course("英语", "2", "1", "2", "3-5", "1-101")}, termStart2025, Adjustments{
+		Calendar:   BundledCalendar(),
+		Exceptions: []Exception{{Kind: Cancel, Course: "英语", Date: "2025-09-28"}},
+	})
+	want := []string{"2025-09-23 英语 1-2 1-101", "2025-09-30 英语 1-2 1-101"}
+	if got := describeSessions(sessions); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
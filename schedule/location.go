@@ -0,0 +1,230 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Place 结构化的上课地点
+type Place struct {
+	Campus   string `json:"campus,omitempty"`
+	Building string `json:"building,omitempty"`
+	Room     string `json:"room,omitempty"`
+	Online   bool   `json:"online,omitempty"`
+	TBD      bool   `json:"tbd,omitempty"` // 未安排或待定
+}
+
+// String 返回简短描述，如 "小营校区 3-101"
+func (p Place) String() string {
+	switch {
+	case p.Online:
+		return "线上"
+	case p.TBD:
+		return "待定"
+	}
+	room := p.Building
+	if p.Room != "" {
+		if room != "" {
+			room += "-"
+		}
+		room += p.Room
+	}
+	return strings.TrimSpace(p.Campus + " " + room)
+}
+
+// LocationTable 地点别名表
+type LocationTable struct {
+	// Campuses 校区名 → 地点中可能出现的前缀，如 "沙河校区": ["沙河", "SH"]
+	Campuses map[string][]string `json:"campuses"`
+	// Buildings 楼名别名 → 统一名称，如 "一教": "1"
+	Buildings map[string]string `json:"buildings"`
+	// BuildingCampus 没有写校区时，按楼名推断所在校区
+	BuildingCampus map[string]string `json:"buildingCampus"`
+	// DefaultCampus 以上都无法判断时使用的校区，为空表示未知
+	DefaultCampus string `json:"defaultCampus"`
+	// Online 表示线上课程的关键字
+	Online []string `json:"online"`
+	// TBD 表示未安排地点的取值
+	TBD []string `json:"tbd"`
+
+	// Travel 两地之间的通行时间（分钟），键为校区名或 "校区/楼"，查找时不区分方向
+	Travel map[string]map[string]int `json:"travel"`
+	// CampusTravel 不同校区之间未在 Travel 中列出时的默认通行时间（分钟）
+	CampusTravel int `json:"campusTravel"`
+
+	// SectionTimes 作息时间与默认不同的校区，校区名 → 各节上下课时间，节数与默认作息表相同
+	SectionTimes map[string][]SectionTime `json:"sectionTimes"`
+}
+
+// DefaultLocationTable 返回 BISTU 各校区的默认别名表
+func DefaultLocationTable() *LocationTable {
+	return &LocationTable{
+		Campuses: map[string][]string{
+			"小营校区":  {"小营"},
+			"健翔桥校区": {"健翔桥"},
+			"清河校区":  {"清河"},
+			"酒仙桥校区": {"酒仙桥"},
+			"沙河校区":  {"沙河", "昌平"},
+		},
+		Buildings:      map[string]string{},
+		BuildingCampus: map[string]string{},
+		Online:         []string{"线上", "在线", "网络", "网课", "腾讯会议", "钉钉", "慕课", "MOOC", "online", "云课堂"},
+		TBD:            []string{"无", "待定", "未安排", "另行通知", "TBD"},
+		Travel:         map[string]map[string]int{},
+		CampusTravel:   60,
+	}
+}
+
+// LoadLocationTable 读取 JSON 别名表并合并到当前表中，同名条目以文件为准
+func (t *LocationTable) LoadLocationTable(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取地点别名表失败: %w", err)
+	}
+	var user LocationTable
+	if err := json.Unmarshal(data, &user); err != nil {
+		return fmt.Errorf("解析地点别名表 %s 失败: %w", path, err)
+	}
+	for k, v := range user.Campuses {
+		t.Campuses[k] = v
+	}
+	for k, v := range user.Buildings {
+		t.Buildings[k] = v
+	}
+	for k, v := range user.BuildingCampus {
+		t.BuildingCampus[k] = v
+	}
+	if user.DefaultCampus != "" {
+		t.DefaultCampus = user.DefaultCampus
+	}
+	for from, row := range user.Travel {
+		if t.Travel[from] == nil {
+			t.Travel[from] = make(map[string]int)
+		}
+		for to, min := range row {
+			t.Travel[from][to] = min
+		}
+	}
+	if user.CampusTravel > 0 {
+		t.CampusTravel = user.CampusTravel
+	}
+	for campus, times := range user.SectionTimes {
+		if err := validateSectionTimes(times); err != nil {
+			return fmt.Errorf("地点别名表 %s 中 %s 的作息时间: %w", path, campus, err)
+		}
+		if t.SectionTimes == nil {
+			t.SectionTimes = make(map[string][]SectionTime)
+		}
+		t.SectionTimes[campus] = times
+	}
+	t.Online = append(t.Online, user.Online...)
+	t.TBD = append(t.TBD, user.TBD...)
+	return nil
+}
+
+var roomRe = regexp.MustCompile(`^(.*?)[-－—_\s]*([A-Za-z]?\d{2,4}[A-Za-z]?)$`)
+
+// ParseLocation 将 placeName 解析为校区、楼和房间，table 为空时使用默认别名表
+func ParseLocation(s string, table *LocationTable) Place {
+	if table == nil {
+		table = DefaultLocationTable()
+	}
+	s = strings.TrimSpace(s)
+	var p Place
+	if s == "" {
+		p.TBD = true
+		return p
+	}
+	for _, v := range table.TBD {
+		if strings.EqualFold(s, v) {
+			p.TBD = true
+			return p
+		}
+	}
+	lower := strings.ToLower(s)
+	for _, k := range table.Online {
+		if strings.Contains(lower, strings.ToLower(k)) {
+			p.Online = true
+			return p
+		}
+	}
+
+	rest := s
+	p.Campus, rest = matchCampus(rest, table)
+	rest = strings.TrimLeft(rest, " -－—_/·")
+
+	if m := roomRe.FindStringSubmatch(rest); m != nil {
+		p.Building, p.Room = strings.TrimSpace(m[1]), m[2]
+	} else {
+		p.Building = rest
+	}
+	if alias, ok := table.Buildings[p.Building]; ok {
+		p.Building = alias
+	}
+	if p.Campus == "" {
+		p.Campus = table.BuildingCampus[p.Building]
+	}
+	if p.Campus == "" {
+		p.Campus = table.DefaultCampus
+	}
+	return p
+}
+
+// matchCampus 匹配最长的校区前缀（含 "校区" 二字的写法），返回校区名和剩余部分
+func matchCampus(s string, table *LocationTable) (string, string) {
+	type alias struct{ prefix, campus string }
+	var aliases []alias
+	for campus, prefixes := range table.Campuses {
+		aliases = append(aliases, alias{campus, campus})
+		for _, p := range prefixes {
+			aliases = append(aliases, alias{p + "校区", campus}, alias{p, campus})
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool { return len(aliases[i].prefix) > len(aliases[j].prefix) })
+	for _, a := range aliases {
+		if strings.HasPrefix(strings.ToUpper(s), strings.ToUpper(a.prefix)) {
+			return a.campus, s[len(a.prefix):]
+		}
+	}
+	return "", s
+}
+
+// CampusTimes 返回校区单独配置的作息时间
+func (t *LocationTable) CampusTimes(campus string) ([]SectionTime, bool) {
+	if t == nil || campus == "" {
+		return nil, false
+	}
+	times, ok := t.SectionTimes[campus]
+	return times, ok
+}
+
+// Times 返回校区的作息时间，没有单独配置时为默认作息表
+func (t *LocationTable) Times(campus string) []SectionTime {
+	if times, ok := t.CampusTimes(campus); ok {
+		return times
+	}
+	return SectionTimes
+}
+
+// retime 按上课地点所在校区的作息时间修正起止时间，换教室后以新地点为准
+func retime(sessions []Session, table *LocationTable) []Session {
+	if table == nil || len(table.SectionTimes) == 0 {
+		return sessions
+	}
+	for i := range sessions {
+		s := &sessions[i]
+		times, ok := table.CampusTimes(ParseLocation(s.Location, table).Campus)
+		if !ok {
+			continue
+		}
+		if from, to, err := sectionSpan(times, s.Start, s.BeginSection, s.EndSection); err == nil {
+			s.Start, s.End = from, to
+		}
+	}
+	sortSessions(sessions)
+	return sessions
+}
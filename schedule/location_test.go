@@ -0,0 +1,173 @@
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	custom := DefaultLocationTable()
+	custom.Buildings["一教"] = "1"
+	custom.BuildingCampus["1"] = "沙河校区"
+	custom.DefaultCampus = "小营校区"
+
+	tests := []struct {
+		in    string
+		table *LocationTable
+		want  Place
+	}{
+		{"小营3-101", nil, Place{Campus: "小营校区", Building: "3", Room: "101"}},
+		{"小营校区 3-101", nil, Place{Campus: "小营校区", Building: "3", Room: "101"}},
+		{"沙河校区-1-201", nil, Place{Campus: "沙河校区", Building: "1", Room: "201"}},
+		{"昌平1-201", nil, Place{Campus: "沙河校区", Building: "1", Room: "201"}},
+		{"健翔桥校区A101", nil, Place{Campus: "健翔桥校区", Room: "A101"}},
+		{"3-101", nil, Place{Building: "3", Room: "101"}},
+		{"操场", nil, Place{Building: "操场"}},
+		{" 小营 操场 ", nil, Place{Campus: "小营校区", Building: "操场"}},
+		{"腾讯会议", nil, Place{Online: true}},
+		{"Mooc 平台", nil, Place{Online: true}},
+		{"无", nil, Place{TBD: true}},
+		{"tbd", nil, Place{TBD: true}},
+		{"", nil, Place{TBD: true}},
+		// 楼名别名、按楼推断校区、默认校区
+		{"一教101", custom, Place{Campus: "沙河校区", Building: "1", Room: "101"}},
+		{"3-101", custom, Place{Campus: "小营校区", Building: "3", Room: "101"}},
+		{"清河5-101", custom, Place{Campus: "清河校区", Building: "5", Room: "101"}},
+	}
+	for _, tt := range tests {
+		if got := ParseLocation(tt.in, tt.table); got != tt.want {
+			t.Errorf("ParseLocation(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceString(t *testing.T) {
+	tests := []struct {
+		in   Place
+		want string
+	}{
+		{Place{Campus: "小营校区", Building: "3", Room: "101"}, "小营校区 3-101"},
+		{Place{Room: "A101"}, "A101"},
+		{Place{Campus: "小营校区", Building: "操场"}, "小营校区 操场"},
+		{Place{Online: true}, "线上"},
+		{Place{TBD: true}, "待定"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("String(%+v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadLocationTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locations.json")
+	data := `{
+		"campuses": {"沙河校区": ["沙河", "SH"]},
+		"buildings": {"一教": "1"},
+		"online": ["企业微信"],
+		"travel": {"小营校区": {"沙河校区": 45}},
+		"campusTravel": 90
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table := DefaultLocationTable()
+	if err := table.LoadLocationTable(path); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in   string
+		want Place
+	}{
+		{"SH一教101", Place{Campus: "沙河校区", Building: "1", Room: "101"}},
+		{"小营3-101", Place{Campus: "小营校区", Building: "3", Room: "101"}}, // 未列出的默认别名保留
+		{"企业微信", Place{Online: true}},
+		{"腾讯会议", Place{Online: true}},
+	}
+	for _, tt := range tests {
+		if got := ParseLocation(tt.in, table); got != tt.want {
+			t.Errorf("ParseLocation(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	xiaoying, shahe, qinghe := Place{Campus: "小营校区"}, Place{Campus: "沙河校区"}, Place{Campus: "清河校区"}
+	if got := table.TravelMinutes(shahe, xiaoying); got != 45 {
+		t.Errorf("TravelMinutes(沙河, 小营) = %d, want 45", got)
+	}
+	if got := table.TravelMinutes(qinghe, xiaoying); got != 90 {
+		t.Errorf("TravelMinutes(清河, 小营) = %d, want 90", got)
+	}
+
+	if err := table.LoadLocationTable(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadLocationTable(missing) = nil, want error")
+	}
+}
+
+func TestCampusSectionTimes(t *testing.T) {
+	// 沙河校区每节推迟 30 分钟
+	shahe := make([]SectionTime, len(SectionTimes))
+	for i, st := range SectionTimes {
+		shahe[i] = SectionTime{shiftClock(st.Begin, 30), shiftClock(st.End, 30)}
+	}
+	table := DefaultLocationTable()
+	table.SectionTimes = map[string][]SectionTime{"沙河校区": shahe}
+
+	courses := []Course{
+		course("高数", "1", "1", "2", "1", "小营3-101"),
+		course("英语", "1", "3", "4", "1", "沙河1-201"),
+		course("物理", "2", "1", "2", "1", "小营3-101"),
+	}
+	sessions := Expand(courses, termStart2025, Adjustments{
+		Locations:  table,
+		Exceptions: []Exception{{Kind: Move, Course: "物理", Date: "2025-09-09", Location: "沙河5-101"}},
+	})
+	var got []string
+	for _, s := range sessions {
+		got = append(got, s.Course.Name+" "+s.Start.In(TZ).Format("01-02 15:04")+"-"+s.End.In(TZ).Format("15:04"))
+	}
+	want := []string{
+		"高数 09-08 08:00-09:35",
+		"英语 09-08 10:20-11:55",
+		"物理 09-09 08:30-10:05", // 换到沙河校区后按沙河校区的作息时间
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expand:\n got  %q\n want %q", got, want)
+	}
+
+	// 跨校区课间按各自校区的作息时间计算：09:35 下课，10:20 上课
+	warnings := FindTravelWarnings(courses[:2], table)
+	if len(warnings) != 1 || warnings[0].Gap != 45 {
+		t.Errorf("FindTravelWarnings = %+v, want one warning with a 45 minute gap", warnings)
+	}
+}
+
+func TestLoadLocationTableSectionTimes(t *testing.T) {
+	tests := []struct {
+		name  string
+		times string
+	}{
+		{"节数不足", `[{"begin":"08:00","end":"08:45"}]`},
+		{"时间格式", `[` + strings.Repeat(`{"begin":"8:00","end":"08:45"},`, len(SectionTimes)-1) + `{"begin":"8:00","end":"08:45"}]`},
+		{"时间倒序", `[` + strings.Repeat(`{"begin":"09:00","end":"08:45"},`, len(SectionTimes)-1) + `{"begin":"09:00","end":"08:45"}]`},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "locations.json")
+		if err := os.WriteFile(path, []byte(`{"sectionTimes":{"沙河校区":`+tt.times+`}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := DefaultLocationTable().LoadLocationTable(path); err == nil {
+			t.Errorf("%s: LoadLocationTable = nil, want error", tt.name)
+		}
+	}
+}
+
+// shiftClock 将 HH:MM 推迟 minutes 分钟
+func shiftClock(clock string, minutes int) string {
+	m := clockMinutes(clock) + minutes
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 不同 jwapp 接口（学期课表、周课表、班级课表）返回的星期和节次形式不一：
+// 整数、浮点数（1.0）、"星期一"、"周一"、"第1节" 等，统一规范为整数
+
+// weekdayAliases 星期的中英文写法（小写比较）
+var weekdayAliases = map[string]int{
+	"一": 1, "二": 2, "三": 3, "四": 4, "五": 5, "六": 6, "日": 7, "天": 7, "七": 7,
+	"mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6, "sun": 7,
+	"monday": 1, "tuesday": 2, "wednesday": 3, "thursday": 4, "friday": 5, "saturday": 6, "sunday": 7,
+}
+
+// NormalizeWeekday 将各种形式的星期规范为 1-7（周一为 1）
+func NormalizeWeekday(s string) (int, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	for _, prefix := range []string{"星期", "礼拜", "周"} {
+		v = strings.TrimPrefix(v, prefix)
+	}
+	v = strings.TrimSpace(v)
+	if day, ok := weekdayAliases[v]; ok {
+		return day, nil
+	}
+	if n, ok := wholeNumber(v); ok && n >= 1 && n <= 7 {
+		return n, nil
+	}
+	return 0, fmt.Errorf("无效的星期: %q", s)
+}
+
+// NormalizeSection 将各种形式的节次规范为正整数，如 "3"、"3.0"、"第3节"、"第三节"
+func NormalizeSection(s string) (int, error) {
+	v := strings.TrimSpace(s)
+	v = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(v, "第"), "节"))
+	if n, ok := wholeNumber(v); ok && n >= 1 {
+		return n, nil
+	}
+	if n, ok := zhNumber(v); ok {
+		return n, nil
+	}
+	return 0, fmt.Errorf("无效的节次: %q", s)
+}
+
+// wholeNumber 解析整数或小数部分为 0 的浮点数
+func wholeNumber(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != float64(int(f)) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+var zhDigits = map[string]int{"一": 1, "二": 2, "三": 3, "四": 4, "五": 5, "六": 6, "七": 7, "八": 8, "九": 9}
+
+// zhNumber 解析 1-99 的中文数字，如 "三"、"十二"、"二十"
+func zhNumber(s string) (int, bool) {
+	digit := func(r string) int { return zhDigits[r] }
+	tens, ones, found := strings.Cut(s, "十")
+	if !found {
+		n := digit(s)
+		return n, n > 0
+	}
+	n := 10
+	if tens != "" {
+		if n = digit(tens) * 10; n == 0 {
+			return 0, false
+		}
+	}
+	if ones != "" {
+		d := digit(ones)
+		if d == 0 {
+			return 0, false
+		}
+		n += d
+	}
+	return n, true
+}
+
+// Rejected 星期或节次无法识别的记录，不会写入课表
+// 来自原始数据时 Record 为第几条记录，来自 CSV 时 Line 为文件中的行号
+type Rejected struct {
+	Record int    `json:"record,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// String 返回单行描述，如 "原始记录 #3 高等数学: 无效的星期: \"星期八\""
+func (r Rejected) String() string {
+	if r.Line > 0 {
+		return fmt.Sprintf("第 %d 行 %s: %s", r.Line, r.Name, r.Reason)
+	}
+	return fmt.Sprintf("原始记录 #%d %s: %s", r.Record, r.Name, r.Reason)
+}
@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeWeekday(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"1", 1},
+		{"7", 7},
+		{"1.0", 1},
+		{"3.00", 3},
+		{" 2 ", 2},
+		{"星期一", 1},
+		{"星期日", 7},
+		{"星期天", 7},
+		{"周三", 3},
+		{"周日", 7},
+		{"礼拜五", 5},
+		{"星期 六", 6},
+		{"一", 1},
+		{"Mon", 1},
+		{"sunday", 7},
+		{"周7", 7},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeWeekday(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeWeekday(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "无", "0", "8", "-1", "1.5", "星期八", "周", "星期一二", "Mo", "abc", "NaN"} {
+		if got, err := NormalizeWeekday(in); err == nil {
+			t.Errorf("NormalizeWeekday(%q) = %d, want error", in, got)
+		}
+	}
+}
+
+func TestNormalizeSection(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"1", 1},
+		{"12", 12},
+		{"3.0", 3},
+		{"第3节", 3},
+		{"3节", 3},
+		{"第三节", 3},
+		{"十", 10},
+		{"十二", 12},
+		{"第十一节", 11},
+		{"二十", 20},
+		{"二十一", 21},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeSection(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeSection(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "无", "0", "-2", "1.5", "第节", "一二", "十十", "零", "百", "3-4", "x"} {
+		if got, err := NormalizeSection(in); err == nil {
+			t.Errorf("NormalizeSection(%q) = %d, want error", in, got)
+		}
+	}
+}
+
+func TestParseRecords(t *testing.T) {
+	raw := []map[string]interface{}{
+		{"courseName": "高数", "dayOfWeek": 1.0, "beginSection": 1, "endSection": 2, "weeksAndTeachers": "1-16周/张三"},
+		{"courseName": "英语", "dayOfWeek": "星期三", "beginSection": "3.0", "endSection": "4.0", "weeksAndTeachers": "1-16周/李四"},
+		{"courseName": "物理", "dayOfWeek": "周五", "beginSection": "第五节", "endSection": "第6节", "weeksAndTeachers": "1-16周/王五"},
+		{"courseName": "体育", "dayOfWeek": "星期八", "beginSection": 1, "endSection": 2},
+		{"courseName": "化学", "dayOfWeek": 2, "beginSection": 1.5, "endSection": 2},
+		{"courseName": "生物", "dayOfWeek": 2, "beginSection": 4, "endSection": 3},
+		{"courseName": "缺失"},
+	}
+	courses, rejected := ParseRecords(raw)
+
+	type slot struct{ name, day, begin, end string }
+	var got []slot
+	for _, c := range courses {
+		got = append(got, slot{c.Name, c.DayOfWeek, c.BeginSection, c.EndSection})
+	}
+	want := []slot{{"高数", "1", "1", "2"}, {"英语", "3", "3", "4"}, {"物理", "5", "5", "6"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("courses = %+v, want %+v", got, want)
+	}
+
+	var records []int
+	for _, r := range rejected {
+		records = append(records, r.Record)
+	}
+	if want := []int{4, 5, 6, 7}; !reflect.DeepEqual(records, want) {
+		t.Errorf("rejected records = %v, want %v (%+v)", records, want, rejected)
+	}
+	// 跳过的记录不影响后续记录的编号
+	if courses[2].Record != 3 {
+		t.Errorf("Record = %d, want 3", courses[2].Record)
+	}
+}
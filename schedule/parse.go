@@ -3,18 +3,79 @@ package schedule
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
 // Course 结构化课程数据
 type Course struct {
-	Name         string
-	DayOfWeek    string
-	BeginSection string
-	EndSection   string
-	Teacher      string
-	Location     string
-	Weeks        string
+	Name         string `json:"name"`
+	DayOfWeek    string `json:"dayOfWeek"`
+	BeginSection string `json:"beginSection"`
+	EndSection   string `json:"endSection"`
+	Teacher      string `json:"teacher"`
+	Location     string `json:"location"`
+	Weeks        string `json:"weeks"`
+
+	// Record 来源于原始数据中的第几条记录（从 1 开始），0 表示未知（如从 CSV 读取）
+	Record int `json:"record,omitempty"`
+
+	// 以下为教务系统中的附加信息，从 CSV 读取时为空
+	Code       string            `json:"code,omitempty"`       // 课程号
+	ClassID    string            `json:"classId,omitempty"`    // 教学班 ID
+	Credits    float64           `json:"credits,omitempty"`    // 学分
+	CourseType string            `json:"courseType,omitempty"` // 课程性质，如 必修
+	Assessment string            `json:"assessment,omitempty"` // 考核方式，如 考试 / 考查
+	Campus     string            `json:"campus,omitempty"`     // 校区
+	Extras     map[string]string `json:"extras,omitempty"`     // 其余未识别的原始字段
+
+	// Teachers 带职称的教师列表；Groups 仅在不同周由不同教师授课时保留各组安排
+	Teachers []Teacher       `json:"teachers,omitempty"`
+	Groups   []TeachingGroup `json:"groups,omitempty"`
+}
+
+// metaKeys 附加信息在不同接口中可能使用的字段名，按顺序取第一个非空值
+var metaKeys = struct {
+	code, classID, credits, courseType, assessment, campus []string
+}{
+	code:       []string{"courseCode", "courseNo", "kch", "KCH"},
+	classID:    []string{"teachingClassId", "teachClassId", "jxbid", "JXBID", "classId"},
+	credits:    []string{"credit", "credits", "xf", "XF"},
+	courseType: []string{"courseType", "courseNature", "kcxz", "KCXZDM_DISPLAY", "KCLBDM_DISPLAY"},
+	assessment: []string{"examMode", "assessmentMethod", "khfs", "KHFSDM_DISPLAY"},
+	campus:     []string{"campusName", "campus", "xqmc", "XXXQDM_DISPLAY"},
+}
+
+// coreKeys 已解析为基本字段的原始字段
+var coreKeys = []string{"courseName", "dayOfWeek", "beginSection", "endSection", "placeName", "weeksAndTeachers"}
+
+// Slot 返回课程的星期（1-7）和起止节次，兼容 "1.0"、"周一" 等写法
+func (c Course) Slot() (day, begin, end int, err error) {
+	if day, err = NormalizeWeekday(c.DayOfWeek); err != nil {
+		return 0, 0, 0, err
+	}
+	begin, err = NormalizeSection(c.BeginSection)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("无效的开始节数: %q", c.BeginSection)
+	}
+	end, err = NormalizeSection(c.EndSection)
+	if err != nil || end < begin {
+		return 0, 0, 0, fmt.Errorf("无效的结束节数: %q", c.EndSection)
+	}
+	return day, begin, end, nil
+}
+
+// normalizeSlot 将星期和节次改写为规范的整数形式，无法识别时保持原值
+func (c *Course) normalizeSlot() {
+	if day, err := NormalizeWeekday(c.DayOfWeek); err == nil {
+		c.DayOfWeek = strconv.Itoa(day)
+	}
+	if n, err := NormalizeSection(c.BeginSection); err == nil {
+		c.BeginSection = strconv.Itoa(n)
+	}
+	if n, err := NormalizeSection(c.EndSection); err == nil {
+		c.EndSection = strconv.Itoa(n)
+	}
 }
 
 var bracketRe = regexp.MustCompile(`\[.*?\]`)
@@ -28,28 +89,10 @@ func ParseCourse(raw map[string]interface{}) Course {
 		EndSection:   getStr(raw, "endSection", "无"),
 		Location:     getStr(raw, "placeName", "无"),
 	}
+	c.normalizeSlot()
 
-	wt := getStr(raw, "weeksAndTeachers", "")
-	if wt != "" {
-		parts := strings.SplitN(wt, "/", 2)
-		if len(parts) > 0 {
-			weeks := parts[0]
-			weeks = bracketRe.ReplaceAllString(weeks, "")
-			weeks = strings.ReplaceAll(weeks, "周", "")
-			weeks = strings.TrimSpace(weeks)
-			if weeks != "" {
-				c.Weeks = weeks
-			}
-		}
-		if len(parts) > 1 {
-			teacher := parts[1]
-			teacher = bracketRe.ReplaceAllString(teacher, "")
-			teacher = strings.TrimSpace(teacher)
-			if teacher != "" {
-				c.Teacher = teacher
-			}
-		}
-	}
+	parseTeaching(&c, getStr(raw, "weeksAndTeachers", ""))
+	parseMeta(&c, raw)
 
 	if c.Teacher == "" {
 		c.Teacher = "无"
@@ -64,13 +107,118 @@ func ParseCourse(raw map[string]interface{}) Course {
 	return c
 }
 
-// ParseAll 批量解析课程列表
+// ParseAll 批量解析课程列表，星期或节次无法识别的记录被跳过
 func ParseAll(rawList []map[string]interface{}) []Course {
+	courses, _ := ParseRecords(rawList)
+	return courses
+}
+
+// ParseRecords 批量解析课程列表，同时返回星期或节次无法识别而被跳过的记录
+func ParseRecords(rawList []map[string]interface{}) ([]Course, []Rejected) {
 	courses := make([]Course, 0, len(rawList))
-	for _, raw := range rawList {
-		courses = append(courses, ParseCourse(raw))
+	var rejected []Rejected
+	for i, raw := range rawList {
+		c := ParseCourse(raw)
+		c.Record = i + 1
+		if _, _, _, err := c.Slot(); err != nil {
+			rejected = append(rejected, Rejected{Record: c.Record, Name: c.Name, Reason: err.Error()})
+			continue
+		}
+		courses = append(courses, c)
+	}
+	return courses, rejected
+}
+
+// parseTeaching 解析周数与教师，格式无法识别时按第一个 "/" 拆分
+func parseTeaching(c *Course, wt string) {
+	if wt == "" {
+		return
+	}
+	groups, err := ParseWeeksAndTeachers(wt)
+	if err != nil {
+		parts := strings.SplitN(wt, "/", 2)
+		c.Weeks = cleanWeeks(parts[0])
+		if len(parts) > 1 {
+			c.Teacher = strings.TrimSpace(bracketRe.ReplaceAllString(parts[1], ""))
+		}
+		return
+	}
+
+	var texts []string
+	var union []int
+	seen := make(map[int]bool)
+	for _, g := range groups {
+		if g.WeeksText != "" {
+			texts = append(texts, g.WeeksText)
+		}
+		for _, w := range g.Weeks {
+			if !seen[w] {
+				seen[w] = true
+				union = append(union, w)
+			}
+		}
+		for _, t := range g.Teachers {
+			if !hasTeacher(c.Teachers, t) {
+				c.Teachers = append(c.Teachers, t)
+			}
+		}
+	}
+	c.Teacher = joinTeachers(c.Teachers)
+	c.Weeks = strings.Join(texts, ",")
+	if len(groups) > 1 {
+		c.Groups = groups
+		// 多组周数可能重叠，合并后重新表示
+		c.Weeks = FormatWeeks(union)
+	}
+}
+
+func hasTeacher(list []Teacher, t Teacher) bool {
+	for _, x := range list {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMeta 提取附加信息，其余字段（个人信息除外）放入 Extras
+func parseMeta(c *Course, raw map[string]interface{}) {
+	used := make(map[string]bool)
+	for _, k := range coreKeys {
+		used[k] = true
+	}
+	first := func(keys []string) string {
+		for _, k := range keys {
+			used[k] = true
+		}
+		for _, k := range keys {
+			if v := getStr(raw, k, ""); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	c.Code = first(metaKeys.code)
+	c.ClassID = first(metaKeys.classID)
+	if v := first(metaKeys.credits); v != "" {
+		c.Credits, _ = strconv.ParseFloat(v, 64)
+	}
+	c.CourseType = first(metaKeys.courseType)
+	c.Assessment = first(metaKeys.assessment)
+	c.Campus = first(metaKeys.campus)
+
+	for k := range raw {
+		if used[k] || redactedKeys[strings.ToLower(k)] {
+			continue
+		}
+		if v := getStr(raw, k, ""); v != "" {
+			if c.Extras == nil {
+				c.Extras = make(map[string]string)
+			}
+			c.Extras[k] = v
+		}
 	}
-	return courses
 }
 
 func getStr(m map[string]interface{}, key, fallback string) string {
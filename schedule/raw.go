@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// RawDump 课表接口原始数据的存档，用于离线解析和排查解析问题
+type RawDump struct {
+	Term      string                   `json:"term"`
+	FetchedAt time.Time                `json:"fetchedAt"`
+	Items     []map[string]interface{} `json:"items"`
+	// NotArranged 未安排时间的课程记录
+	NotArranged []map[string]interface{} `json:"notArranged,omitempty"`
+}
+
+// redactedKeys 原始记录中可能包含个人信息的字段（小写比较）
+var redactedKeys = map[string]bool{
+	"xh": true, "xm": true, "studentcode": true, "studentname": true,
+	"userid": true, "username": true, "usrid": true,
+	"sfzjh": true, "idcard": true, "phone": true, "sjh": true, "email": true,
+}
+
+const redacted = "***"
+
+// Redact 返回脱敏后的记录副本
+// 去掉学号、姓名等个人字段，并替换其他字段中出现的学号
+func Redact(items []map[string]interface{}, studentID string) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		out = append(out, redactMap(item, studentID))
+	}
+	return out
+}
+
+func redactMap(m map[string]interface{}, studentID string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if redactedKeys[strings.ToLower(k)] {
+			out[k] = redacted
+			continue
+		}
+		out[k] = redactValue(v, studentID)
+	}
+	return out
+}
+
+func redactValue(v interface{}, studentID string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return redactMap(val, studentID)
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, e := range val {
+			list[i] = redactValue(e, studentID)
+		}
+		return list
+	case string:
+		if studentID != "" {
+			return strings.ReplaceAll(val, studentID, redacted)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// SaveRaw 将原始记录脱敏后写入文件
+func SaveRaw(filename, termCode, studentID string, data *ScheduleData) error {
+	dump := RawDump{
+		Term:      termCode,
+		FetchedAt: time.Now(),
+		Items:     Redact(data.Arranged, studentID),
+	}
+	if len(data.NotArranged) > 0 {
+		dump.NotArranged = Redact(data.NotArranged, studentID)
+	}
+	out, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化原始数据失败: %w", err)
+	}
+	out = append(out, '\n')
+	if err := os.WriteFile(filename, out, 0o644); err != nil {
+		return fmt.Errorf("保存原始数据失败: %w", err)
+	}
+	return nil
+}
+
+// LoadRaw 读取原始数据文件
+// 既支持 SaveRaw 写出的存档，也支持直接从浏览器保存的 getMyScheduleDetail.do 响应
+func LoadRaw(filename string) (*RawDump, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("读取原始数据失败: %w", err)
+	}
+
+	var dump RawDump
+	if err := json.Unmarshal(data, &dump); err == nil && len(dump.Items)+len(dump.NotArranged) > 0 {
+		return &dump, nil
+	}
+
+	resp, err := ParseScheduleResponse(data)
+	if err != nil {
+		return nil, fmt.Errorf("无法识别原始数据 %s: %w", filename, err)
+	}
+	return &RawDump{Items: resp.Arranged, NotArranged: resp.NotArranged}, nil
+}
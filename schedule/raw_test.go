@@ -0,0 +1,105 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	items := []map[string]interface{}{{
+		"courseName": "高数",
+		"XH":         "2023011111",
+		"xm":         "张同学",
+		"remark":     "学号 2023011111 的重修",
+		"credit":     4.0,
+		"detail":     map[string]interface{}{"StudentCode": "2023011111", "class": "计科2301-2023011111"},
+		"list":       []interface{}{"2023011111", 1.0},
+	}}
+	got := Redact(items, "2023011111")
+	want := []map[string]interface{}{{
+		"courseName": "高数",
+		"XH":         "***",
+		"xm":         "***",
+		"remark":     "学号 *** 的重修",
+		"credit":     4.0,
+		"detail":     map[string]interface{}{"StudentCode": "***", "class": "计科2301-***"},
+		"list":       []interface{}{"***", 1.0},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact:\n got  %+v\n want %+v", got, want)
+	}
+	// 不修改原始记录
+	if items[0]["XH"] != "2023011111" {
+		t.Errorf("Redact modified its input: %+v", items[0])
+	}
+	// 学号未知时只去掉个人字段
+	if got := Redact(items, ""); got[0]["remark"] != "学号 2023011111 的重修" || got[0]["xm"] != "***" {
+		t.Errorf("Redact without student ID = %+v", got[0])
+	}
+}
+
+func TestLoadRaw(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	saved := filepath.Join(dir, "saved.json")
+	data := &ScheduleData{
+		Arranged:    []map[string]interface{}{{"courseName": "高数", "xh": "2023011111"}},
+		NotArranged: []map[string]interface{}{{"courseName": "专业实习"}},
+	}
+	if err := SaveRaw(saved, "2025-2026-1", "2023011111", data); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		term        string
+		items       []string
+		notArranged []string
+	}{
+		{"SaveRaw 的存档", saved, "2025-2026-1", []string{"高数"}, []string{"专业实习"}},
+		{"浏览器保存的接口响应", write("resp.json", `{"datas":{"arrangedList":[{"courseName":"英语"}]}}`), "", []string{"英语"}, nil},
+		{"只有未安排课程的存档", write("na.json", `{"term":"2025-2026-2","notArranged":[{"courseName":"毕业设计"}]}`), "2025-2026-2", nil, []string{"毕业设计"}},
+	}
+	for _, tt := range tests {
+		dump, err := LoadRaw(tt.path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if dump.Term != tt.term {
+			t.Errorf("%s: Term = %q, want %q", tt.name, dump.Term, tt.term)
+		}
+		if got := recordNames(dump.Items); !reflect.DeepEqual(got, tt.items) {
+			t.Errorf("%s: items = %q, want %q", tt.name, got, tt.items)
+		}
+		if got := recordNames(dump.NotArranged); !reflect.DeepEqual(got, tt.notArranged) {
+			t.Errorf("%s: notArranged = %q, want %q", tt.name, got, tt.notArranged)
+		}
+	}
+
+	// 存档已脱敏
+	dump, _ := LoadRaw(saved)
+	if dump.Items[0]["xh"] != "***" {
+		t.Errorf("saved raw not redacted: %+v", dump.Items[0])
+	}
+
+	for _, path := range []string{
+		filepath.Join(dir, "missing.json"),
+		write("empty.json", `{"term":"2025-2026-1","items":[]}`),
+		write("bad.json", `not json`),
+	} {
+		if dump, err := LoadRaw(path); err == nil {
+			t.Errorf("LoadRaw(%s) = %+v, want error", filepath.Base(path), dump)
+		}
+	}
+}
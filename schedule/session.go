@@ -0,0 +1,221 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session 课程的一次具体上课（某周某天某几节）
+// 节次和地点通常与课程相同，调课后以这里为准
+type Session struct {
+	Course       *Course
+	Week         int
+	Start        time.Time
+	End          time.Time
+	BeginSection int
+	EndSection   int
+	Location     string
+	Note         string // 调课、补课等说明，正常上课为空
+}
+
+// Covers 判断这次课是否包含第 section 节，0 表示不限节次
+// 相邻节次合并后一次课可能跨多段，按起止范围判断而不只看开始节次
+func (s Session) Covers(section int) bool {
+	return section == 0 || (section >= s.BeginSection && section <= s.EndSection)
+}
+
+var weeksReplacer = strings.NewReplacer(
+	"周", "", "，", ",", "、", ",", " ", "",
+	"(", "", ")", "", "（", "", "）", "",
+)
+
+// ParseWeeks 解析周数表达式，如 "1-16"、"1-15单"、"2-16(双)"、"1-8,10-16"
+func ParseWeeks(s string) ([]int, error) {
+	s = weeksReplacer.Replace(s)
+	if s == "" || s == "无" {
+		return nil, fmt.Errorf("周数为空")
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		if part == "" {
+			continue
+		}
+		parity := 0
+		if strings.HasSuffix(part, "单") {
+			parity = 1
+			part = strings.TrimSuffix(part, "单")
+		} else if strings.HasSuffix(part, "双") {
+			parity = 2
+			part = strings.TrimSuffix(part, "双")
+		}
+
+		lo, hi, err := parseRange(part)
+		if err != nil {
+			return nil, fmt.Errorf("无效的周数 %q", s)
+		}
+		for w := lo; w <= hi; w++ {
+			if (parity == 1 && w%2 == 0) || (parity == 2 && w%2 == 1) {
+				continue
+			}
+			seen[w] = true
+		}
+	}
+
+	weeks := make([]int, 0, len(seen))
+	for w := range seen {
+		weeks = append(weeks, w)
+	}
+	sort.Ints(weeks)
+	return weeks, nil
+}
+
+// parseRange 解析 "3" 或 "1-16" 形式的闭区间
+func parseRange(s string) (int, int, error) {
+	if lo, hi, ok := strings.Cut(s, "-"); ok {
+		a, err := strconv.Atoi(lo)
+		if err != nil {
+			return 0, 0, err
+		}
+		b, err := strconv.Atoi(hi)
+		if err != nil {
+			return 0, 0, err
+		}
+		if a < 1 || b < a {
+			return 0, 0, fmt.Errorf("区间无效: %s", s)
+		}
+		return a, b, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, 0, fmt.Errorf("周数无效: %s", s)
+	}
+	return n, n, nil
+}
+
+// Adjustments 展开课程时需要考虑的节假日、单次调整和各校区作息时间，零值表示不调整
+type Adjustments struct {
+	Calendar   *Calendar
+	Exceptions []Exception
+	Locations  *LocationTable
+}
+
+// Expand 以学期第一周周一为基准，把课程展开为逐次上课安排（按开始时间排序）
+// 周数、星期或节次无法识别的课程会被跳过
+// 随后先按节假日停课、调休，再应用用户记录的停课、调课和补课，最后按所在校区的作息时间修正上下课时间
+func Expand(courses []Course, termStart time.Time, adj Adjustments) []Session {
+	start := dayOf(termStart)
+	var sessions []Session
+	for i := range courses {
+		c := &courses[i]
+		day, begin, end, err := c.Slot()
+		if err != nil {
+			continue
+		}
+		weeks, err := ParseWeeks(c.Weeks)
+		if err != nil {
+			continue
+		}
+		for _, w := range weeks {
+			date := start.AddDate(0, 0, (w-1)*7+day-1)
+			from, to, err := SectionSpan(date, begin, end)
+			if err != nil {
+				continue
+			}
+			sessions = append(sessions, Session{
+				Course: c, Week: w, Start: from, End: to,
+				BeginSection: begin, EndSection: end, Location: c.Location,
+			})
+		}
+	}
+	sortSessions(sessions)
+	sessions = applyHolidays(sessions, start, adj.Calendar)
+	sessions = applyExceptions(sessions, courses, start, adj.Exceptions)
+	return retime(sessions, adj.Locations)
+}
+
+func sortSessions(sessions []Session) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+}
+
+// FormatWeeks 将周数列表压缩为表达式，如 [1 2 3 5] → "1-3,5"，[1 3 5 7] → "1-7单"
+func FormatWeeks(weeks []int) string {
+	if len(weeks) == 0 {
+		return ""
+	}
+	sorted := append([]int(nil), weeks...)
+	sort.Ints(sorted)
+
+	if len(sorted) > 2 {
+		alternate := true
+		for i := 1; i < len(sorted); i++ {
+			if sorted[i]-sorted[i-1] != 2 {
+				alternate = false
+				break
+			}
+		}
+		if alternate {
+			suffix := "双"
+			if sorted[0]%2 == 1 {
+				suffix = "单"
+			}
+			return fmt.Sprintf("%d-%d%s", sorted[0], sorted[len(sorted)-1], suffix)
+		}
+	}
+
+	var parts []string
+	lo := sorted[0]
+	for i := 1; i <= len(sorted); i++ {
+		if i < len(sorted) && sorted[i] <= sorted[i-1]+1 {
+			continue
+		}
+		hi := sorted[i-1]
+		if lo == hi {
+			parts = append(parts, strconv.Itoa(lo))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", lo, hi))
+		}
+		if i < len(sorted) {
+			lo = sorted[i]
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// OnDate 返回某一天的上课安排
+func OnDate(sessions []Session, date time.Time) []Session {
+	day := dayOf(date)
+	var out []Session
+	for _, s := range sessions {
+		if dayOf(s.Start).Equal(day) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Current 返回此刻正在上的课，没有则返回 nil
+func Current(sessions []Session, now time.Time) *Session {
+	for i := range sessions {
+		s := &sessions[i]
+		if !now.Before(s.Start) && now.Before(s.End) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Next 返回此刻之后开始的第一节课，没有则返回 nil（sessions 需按开始时间排序）
+func Next(sessions []Session, now time.Time) *Session {
+	for i := range sessions {
+		if sessions[i].Start.After(now) {
+			return &sessions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package schedule
+
+import (
+	"sort"
+	"strings"
+)
+
+// EveningSection 晚课从第几节开始（18:30）
+const EveningSection = 12
+
+// Load 某一项（课程、教师等）的课时统计
+// Sections 为学时（节数），Sessions 为上课次数
+type Load struct {
+	Name     string `json:"name"`
+	Sections int    `json:"sections"`
+	Sessions int    `json:"sessions"`
+	Minutes  int    `json:"minutes"`
+}
+
+// WeekLoad 某一周的课时
+type WeekLoad struct {
+	Week     int `json:"week"`
+	Sections int `json:"sections"`
+	Sessions int `json:"sessions"`
+}
+
+// DayLoad 某个星期几在整个学期的课时
+type DayLoad struct {
+	Day      int `json:"day"`
+	Sections int `json:"sections"`
+	Sessions int `json:"sessions"`
+}
+
+// ClassTime 一次课的时间点，用于最早、最晚上课时间
+type ClassTime struct {
+	Time   string `json:"time"`
+	Day    int    `json:"day"`
+	Course string `json:"course"`
+}
+
+// Stats 课表统计
+type Stats struct {
+	Courses  int `json:"courses"`
+	Sections int `json:"sections"`
+	Sessions int `json:"sessions"`
+	Minutes  int `json:"minutes"`
+
+	Weeks      []WeekLoad `json:"weeks"`
+	ByCourse   []Load     `json:"byCourse"`
+	ByTeacher  []Load     `json:"byTeacher"`
+	ByCampus   []Load     `json:"byCampus"`
+	ByWeekday  []DayLoad  `json:"byWeekday"`
+	Busiest    []int      `json:"busiestDays"`
+	Emptiest   []int      `json:"emptiestDays"`
+	FirstClass *ClassTime `json:"firstClass,omitempty"`
+	LastClass  *ClassTime `json:"lastClass,omitempty"`
+
+	EveningSessions int     `json:"eveningSessions"`
+	EveningShare    float64 `json:"eveningShare"`
+}
+
+// ComputeStats 按周次展开课程统计课时，无法解析星期、节次或周次的课程不计入
+// 连上的几节算一次课（调用方应先用 MergeAdjacent 合并）；地点按 table 归入校区，table 为空时使用默认别名表
+func ComputeStats(courses []Course, table *LocationTable) *Stats {
+	if table == nil {
+		table = DefaultLocationTable()
+	}
+	s := &Stats{}
+	weeks := make(map[int]*WeekLoad)
+	byCourse := make(map[string]*Load)
+	byTeacher := make(map[string]*Load)
+	byCampus := make(map[string]*Load)
+	var days [7]DayLoad
+	labels := entityLabels(courses)
+	entities := make(map[string]bool)
+
+	for _, c := range courses {
+		day, begin, end, err := c.Slot()
+		if err != nil || day < 1 || day > 7 || begin < 1 || end < begin || end > len(SectionTimes) {
+			continue
+		}
+		ws, err := ParseWeeks(c.Weeks)
+		if err != nil || len(ws) == 0 {
+			continue
+		}
+		entities[entityKey(c)] = true
+		n := len(ws)
+		sections := end - begin + 1
+		minutes := sessionMinutes(begin, end)
+
+		s.Sessions += n
+		s.Sections += n * sections
+		s.Minutes += n * minutes
+		if begin >= EveningSection {
+			s.EveningSessions += n
+		}
+		for _, w := range ws {
+			wl := weeks[w]
+			if wl == nil {
+				wl = &WeekLoad{Week: w}
+				weeks[w] = wl
+			}
+			wl.Sections += sections
+			wl.Sessions++
+		}
+		days[day-1].Sections += n * sections
+		days[day-1].Sessions += n
+
+		addLoad(byCourse, labels[entityKey(c)], n, sections, minutes)
+		addLoad(byCampus, campusLabel(ParseLocation(c.Location, table)), n, sections, minutes)
+		for _, t := range splitTeachers(c.Teacher) {
+			addLoad(byTeacher, t, n, sections, minutes)
+		}
+
+		first := ClassTime{Time: SectionTimes[begin-1].Begin, Day: day, Course: c.Name}
+		if s.FirstClass == nil || first.Time < s.FirstClass.Time {
+			s.FirstClass = &first
+		}
+		last := ClassTime{Time: SectionTimes[end-1].End, Day: day, Course: c.Name}
+		if s.LastClass == nil || last.Time > s.LastClass.Time {
+			s.LastClass = &last
+		}
+	}
+
+	s.Courses = len(entities)
+	if s.Sessions > 0 {
+		s.EveningShare = float64(s.EveningSessions) / float64(s.Sessions)
+	}
+	for _, wl := range weeks {
+		s.Weeks = append(s.Weeks, *wl)
+	}
+	sort.Slice(s.Weeks, func(i, j int) bool { return s.Weeks[i].Week < s.Weeks[j].Week })
+	s.ByCourse = sortedLoads(byCourse)
+	s.ByTeacher = sortedLoads(byTeacher)
+	s.ByCampus = sortedLoads(byCampus)
+
+	// 周末没课时只比较周一到周五
+	shown := 5
+	if days[5].Sessions > 0 || days[6].Sessions > 0 {
+		shown = 7
+	}
+	for i := 0; i < shown; i++ {
+		days[i].Day = i + 1
+		s.ByWeekday = append(s.ByWeekday, days[i])
+	}
+	s.Busiest, s.Emptiest = extremeDays(s.ByWeekday)
+	return s
+}
+
+// entityLabels 为每个教学班生成显示名称，同名的不同教学班附上教师区分
+func entityLabels(courses []Course) map[string]string {
+	count := make(map[string]int)
+	for _, e := range GroupCourses(courses, nil) {
+		count[e.Name]++
+	}
+	labels := make(map[string]string)
+	for _, c := range courses {
+		label := c.Name
+		if count[c.Name] > 1 {
+			label += "（" + c.Teacher + "）"
+		}
+		labels[entityKey(c)] = label
+	}
+	return labels
+}
+
+// campusLabel 统计时使用的校区名称
+func campusLabel(p Place) string {
+	switch {
+	case p.Online:
+		return "线上"
+	case p.TBD:
+		return "地点待定"
+	case p.Campus == "":
+		return "未知校区"
+	}
+	return p.Campus
+}
+
+func sessionMinutes(begin, end int) int {
+	return clockMinutes(SectionTimes[end-1].End) - clockMinutes(SectionTimes[begin-1].Begin)
+}
+
+// clockMinutes 将 "HH:MM" 转换为当天的分钟数
+func clockMinutes(clock string) int {
+	if len(clock) != 5 {
+		return 0
+	}
+	return int(clock[0]-'0')*600 + int(clock[1]-'0')*60 + int(clock[3]-'0')*10 + int(clock[4]-'0')
+}
+
+func addLoad(m map[string]*Load, name string, sessions, sections, minutes int) {
+	l := m[name]
+	if l == nil {
+		l = &Load{Name: name}
+		m[name] = l
+	}
+	l.Sessions += sessions
+	l.Sections += sessions * sections
+	l.Minutes += sessions * minutes
+}
+
+// sortedLoads 按学时从多到少排序，学时相同时按名称
+func sortedLoads(m map[string]*Load) []Load {
+	loads := make([]Load, 0, len(m))
+	for _, l := range m {
+		loads = append(loads, *l)
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].Sections != loads[j].Sections {
+			return loads[i].Sections > loads[j].Sections
+		}
+		return loads[i].Name < loads[j].Name
+	})
+	return loads
+}
+
+// extremeDays 返回学时最多和最少的星期几（并列时都返回）
+func extremeDays(days []DayLoad) (busiest, emptiest []int) {
+	if len(days) == 0 {
+		return nil, nil
+	}
+	max, min := days[0].Sections, days[0].Sections
+	for _, d := range days {
+		if d.Sections > max {
+			max = d.Sections
+		}
+		if d.Sections < min {
+			min = d.Sections
+		}
+	}
+	for _, d := range days {
+		if d.Sections == max {
+			busiest = append(busiest, d.Day)
+		}
+		if d.Sections == min {
+			emptiest = append(emptiest, d.Day)
+		}
+	}
+	return busiest, emptiest
+}
+
+// splitTeachers 拆分 "张三,李四" 这类多位教师，未填写时记为 "未知"
+func splitTeachers(s string) []string {
+	out := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、' || r == ';' || r == '；' || r == ' '
+	})
+	if len(out) == 0 {
+		return []string{"未知"}
+	}
+	return out
+}
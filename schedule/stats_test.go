@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	evening := course("英语", "2", "12", "13", "1", "沙河1-201")
+	evening.Teacher = "李四,王五"
+	courses := []Course{
+		course("高数", "1", "1", "2", "1-2", "小营3-101"),
+		course("高数", "3", "3", "4", "1-2", "小营3-101"),
+		evening,
+		course("实践", "无", "无", "无", "无", "无"), // 无法解析，不计入
+	}
+	got := ComputeStats(courses, nil)
+
+	// 每次课 2 节、95 分钟
+	want := &Stats{
+		Courses: 2, Sections: 10, Sessions: 5, Minutes: 475,
+		Weeks: []WeekLoad{{Week: 1, Sections: 6, Sessions: 3}, {Week: 2, Sections: 4, Sessions: 2}},
+		ByCourse: []Load{
+			{Name: "高数", Sections: 8, Sessions: 4, Minutes: 380},
+			{Name: "英语", Sections: 2, Sessions: 1, Minutes: 95},
+		},
+		ByTeacher: []Load{
+			{Name: "张三", Sections: 8, Sessions: 4, Minutes: 380},
+			{Name: "李四", Sections: 2, Sessions: 1, Minutes: 95},
+			{Name: "王五", Sections: 2, Sessions: 1, Minutes: 95},
+		},
+		ByCampus: []Load{
+			{Name: "小营校区", Sections: 8, Sessions: 4, Minutes: 380},
+			{Name: "沙河校区", Sections: 2, Sessions: 1, Minutes: 95},
+		},
+		// 周末没课时只列周一到周五
+		ByWeekday: []DayLoad{
+			{Day: 1, Sections: 4, Sessions: 2}, {Day: 2, Sections: 2, Sessions: 1}, {Day: 3, Sections: 4, Sessions: 2},
+			{Day: 4}, {Day: 5},
+		},
+		Busiest:         []int{1, 3},
+		Emptiest:        []int{4, 5},
+		FirstClass:      &ClassTime{Time: "08:00", Day: 1, Course: "高数"},
+		LastClass:       &ClassTime{Time: "20:05", Day: 2, Course: "英语"},
+		EveningSessions: 1,
+		EveningShare:    0.2,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeStats:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+func TestComputeStatsLabels(t *testing.T) {
+	other := course("高数", "6", "1", "2", "1", "线上")
+	other.Teacher = "赵六"
+	got := ComputeStats([]Course{course("高数", "1", "1", "2", "1", "待定"), other}, nil)
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"同名的不同教学班附上教师", loadNames(got.ByCourse), []string{"高数（张三）", "高数（赵六）"}},
+		{"线上和待定单独归类", loadNames(got.ByCampus), []string{"地点待定", "线上"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	// 周末有课时列出七天
+	if len(got.ByWeekday) != 7 {
+		t.Errorf("ByWeekday = %+v, want 7 days", got.ByWeekday)
+	}
+	if got.Courses != 2 {
+		t.Errorf("Courses = %d, want 2", got.Courses)
+	}
+}
+
+func loadNames(loads []Load) []string {
+	var out []string
+	for _, l := range loads {
+		out = append(out, l.Name)
+	}
+	return out
+}
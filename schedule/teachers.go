@@ -0,0 +1,193 @@
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Teacher 授课教师，Title 为原始数据方括号中的职称或角色，如 教授、助教
+type Teacher struct {
+	Name  string `json:"name"`
+	Title string `json:"title,omitempty"`
+}
+
+// TeachingGroup weeksAndTeachers 中的一组：这些周由这些教师授课
+type TeachingGroup struct {
+	Weeks     []int     `json:"weeks"`
+	WeeksText string    `json:"weeksText"`
+	Teachers  []Teacher `json:"teachers"`
+}
+
+// TeacherNames 返回教师姓名，以逗号分隔
+func (g TeachingGroup) TeacherNames() string {
+	return joinTeachers(g.Teachers)
+}
+
+var (
+	teacherRe   = regexp.MustCompile(`^(.*?)\s*\[(.*?)\]\s*$`)
+	weekTokenRe = regexp.MustCompile(`^[第\d(（]`)
+)
+
+// ParseWeeksAndTeachers 解析 weeksAndTeachers 字段，如
+//
+//	"1-16周/张三[教授]"
+//	"1-8周,10-16周/张三[教授],李四[助教]"
+//	"1-8周/张三[教授];9-16周/李四[讲师]"
+//	"1-8周/张三,9-16周/李四"
+//
+// 每组由周数和 "/" 之后的教师组成；组之间以分号分隔，或在教师之后直接跟下一组的周数
+func ParseWeeksAndTeachers(s string) ([]TeachingGroup, error) {
+	var groups []TeachingGroup
+	var weeks []string
+	var teachers []Teacher
+	inTeachers := false
+
+	flush := func() error {
+		if len(weeks) == 0 && len(teachers) == 0 {
+			return nil
+		}
+		g := TeachingGroup{WeeksText: strings.Join(weeks, ","), Teachers: teachers}
+		if g.WeeksText != "" {
+			w, err := ParseWeeks(g.WeeksText)
+			if err != nil {
+				return err
+			}
+			g.Weeks = w
+		}
+		groups = append(groups, g)
+		weeks, teachers, inTeachers = nil, nil, false
+		return nil
+	}
+
+	for _, segment := range splitOutsideBrackets(s, func(r rune) bool { return r == ';' || r == '；' }) {
+		for _, token := range splitOutsideBrackets(segment, func(r rune) bool { return r == ',' || r == '，' || r == '、' }) {
+			token = strings.TrimSpace(token)
+			if token == "" {
+				continue
+			}
+			left, right, hasSlash := strings.Cut(token, "/")
+			switch {
+			case hasSlash:
+				if inTeachers {
+					if err := flush(); err != nil {
+						return nil, err
+					}
+				}
+				if w := cleanWeeks(left); w != "" {
+					weeks = append(weeks, w)
+				}
+				inTeachers = true
+				if t, ok := parseTeacher(right); ok {
+					teachers = append(teachers, t)
+				}
+			case weekTokenRe.MatchString(token):
+				if inTeachers {
+					if err := flush(); err != nil {
+						return nil, err
+					}
+				}
+				weeks = append(weeks, cleanWeeks(token))
+			default:
+				if !inTeachers {
+					return nil, fmt.Errorf("无法识别的周数 %q", token)
+				}
+				if t, ok := parseTeacher(token); ok {
+					teachers = append(teachers, t)
+				}
+			}
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("周数与教师为空")
+	}
+	return groups, nil
+}
+
+// splitOutsideBrackets 与 strings.FieldsFunc 相同，但方括号内的分隔符不拆分
+// 职称中可能带逗号，如 "张三[教授,博导]"
+func splitOutsideBrackets(s string, sep func(rune) bool) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch {
+		case r == '[' || r == '【':
+			depth++
+		case (r == ']' || r == '】') && depth > 0:
+			depth--
+		case depth == 0 && sep(r):
+			if i > start {
+				fields = append(fields, s[start:i])
+			}
+			start = i + len(string(r))
+		}
+	}
+	if start < len(s) {
+		fields = append(fields, s[start:])
+	}
+	return fields
+}
+
+// cleanWeeks 去掉 "第"、"周" 和方括号备注，保留单双周标记
+func cleanWeeks(s string) string {
+	s = bracketRe.ReplaceAllString(s, "")
+	s = strings.NewReplacer("第", "", "周", "").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+func parseTeacher(s string) (Teacher, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Teacher{}, false
+	}
+	if m := teacherRe.FindStringSubmatch(s); m != nil {
+		if m[1] == "" {
+			return Teacher{}, false
+		}
+		return Teacher{Name: m[1], Title: strings.TrimSpace(m[2])}, true
+	}
+	return Teacher{Name: s}, true
+}
+
+// joinTeachers 按出现顺序去重后以逗号连接姓名
+func joinTeachers(teachers []Teacher) string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range teachers {
+		if !seen[t.Name] {
+			seen[t.Name] = true
+			names = append(names, t.Name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// SplitGroups 将不同周由不同教师授课的课程拆分为多条，每组一条
+// 其余课程原样保留
+func SplitGroups(courses []Course) []Course {
+	out := make([]Course, 0, len(courses))
+	for _, c := range courses {
+		if len(c.Groups) < 2 {
+			out = append(out, c)
+			continue
+		}
+		for _, g := range c.Groups {
+			part := c
+			part.Groups = nil
+			part.Weeks = g.WeeksText
+			part.Teacher = g.TeacherNames()
+			part.Teachers = g.Teachers
+			if part.Teacher == "" {
+				part.Teacher = "无"
+			}
+			if part.Weeks == "" {
+				part.Weeks = c.Weeks
+			}
+			out = append(out, part)
+		}
+	}
+	return out
+}
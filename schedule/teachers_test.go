@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWeeksAndTeachers(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []TeachingGroup
+	}{
+		{
+			in: "1-16周/张三[教授]",
+			want: []TeachingGroup{
+				{Weeks: span(1, 16), WeeksText: "1-16", Teachers: []Teacher{{"张三", "教授"}}},
+			},
+		},
+		{
+			in: "1-8周,10-16周/张三[教授],李四[助教]",
+			want: []TeachingGroup{
+				{Weeks: append(span(1, 8), span(10, 16)...), WeeksText: "1-8,10-16",
+					Teachers: []Teacher{{"张三", "教授"}, {"李四", "助教"}}},
+			},
+		},
+		{
+			in: "1-8周/张三[教授];9-16周/李四[讲师]",
+			want: []TeachingGroup{
+				{Weeks: span(1, 8), WeeksText: "1-8", Teachers: []Teacher{{"张三", "教授"}}},
+				{Weeks: span(9, 16), WeeksText: "9-16", Teachers: []Teacher{{"李四", "讲师"}}},
+			},
+		},
+		{
+			// 没有分号，教师之后直接跟下一组周数
+			in: "1-8周/张三,9-16周/李四",
+			want: []TeachingGroup{
+				{Weeks: span(1, 8), WeeksText: "1-8", Teachers: []Teacher{{Name: "张三"}}},
+				{Weeks: span(9, 16), WeeksText: "9-16", Teachers: []Teacher{{Name: "李四"}}},
+			},
+		},
+		{
+			in: "1-15周(单)/王五；2-16周(双)/赵六、钱七[助教]",
+			want: []TeachingGroup{
+				{Weeks: []int{1, 3, 5, 7, 9, 11, 13, 15}, WeeksText: "1-15(单)", Teachers: []Teacher{{Name: "王五"}}},
+				{Weeks: []int{2, 4, 6, 8, 10, 12, 14, 16}, WeeksText: "2-16(双)",
+					Teachers: []Teacher{{Name: "赵六"}, {"钱七", "助教"}}},
+			},
+		},
+		{
+			// 职称中的逗号不拆分教师
+			in: "1-16周/张三[教授,博导]",
+			want: []TeachingGroup{
+				{Weeks: span(1, 16), WeeksText: "1-16", Teachers: []Teacher{{"张三", "教授,博导"}}},
+			},
+		},
+		{
+			in: "1-8周/张三[教授，博导];9-16周/李四[讲师；外聘],王五",
+			want: []TeachingGroup{
+				{Weeks: span(1, 8), WeeksText: "1-8", Teachers: []Teacher{{"张三", "教授，博导"}}},
+				{Weeks: span(9, 16), WeeksText: "9-16", Teachers: []Teacher{{"李四", "讲师；外聘"}, {Name: "王五"}}},
+			},
+		},
+		{
+			// 只有周数、没有教师
+			in:   "3-4周/",
+			want: []TeachingGroup{{Weeks: []int{3, 4}, WeeksText: "3-4"}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseWeeksAndTeachers(tt.in)
+		if err != nil {
+			t.Errorf("ParseWeeksAndTeachers(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseWeeksAndTeachers(%q)\n got  %+v\n want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseWeeksAndTeachersErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		";；",
+		"张三",             // 没有周数
+		"16-1周/张三",       // 区间颠倒
+		"0周/张三",          // 周数从 1 开始
+		"1-x周/张三",        // 区间端点不是数字
+		"1-8周/张三;9-周/李四", // 第二组周数不完整
+	} {
+		if got, err := ParseWeeksAndTeachers(in); err == nil {
+			t.Errorf("ParseWeeksAndTeachers(%q) = %+v, want error", in, got)
+		}
+	}
+}
+
+func TestParseCourseTeachers(t *testing.T) {
+	c := ParseCourse(map[string]interface{}{
+		"courseName": "大学物理", "dayOfWeek": 2, "beginSection": 1, "endSection": 2,
+		"placeName": "3-101", "weeksAndTeachers": "1-8周/张三[教授];5-12周/李四[讲师]",
+	})
+	if c.Teacher != "张三,李四" {
+		t.Errorf("Teacher = %q, want 张三,李四", c.Teacher)
+	}
+	// 两组周数重叠，合并后重新表示
+	if c.Weeks != "1-12" {
+		t.Errorf("Weeks = %q, want 1-12", c.Weeks)
+	}
+	if len(c.Groups) != 2 {
+		t.Errorf("Groups = %+v, want 2 groups", c.Groups)
+	}
+
+	// 无法识别时退回按第一个 "/" 拆分
+	c = ParseCourse(map[string]interface{}{"courseName": "体育", "weeksAndTeachers": "全学期/张三[教授]"})
+	if c.Weeks != "全学期" || c.Teacher != "张三" || c.Groups != nil {
+		t.Errorf("fallback: Weeks=%q Teacher=%q Groups=%+v", c.Weeks, c.Teacher, c.Groups)
+	}
+}
+
+func TestParseWeeks(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{"1-4", []int{1, 2, 3, 4}},
+		{"1-7单", []int{1, 3, 5, 7}},
+		{"2-8(双)", []int{2, 4, 6, 8}},
+		{"1-3，5、7", []int{1, 2, 3, 5, 7}},
+		{"3,1-2,2", []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		got, err := ParseWeeks(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseWeeks(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	// "第" 由 cleanWeeks 去掉，ParseWeeks 不接受
+	for _, in := range []string{"", "无", "a-b", "5-3", "0", "-1", "1-", "1--3", "第1-2周"} {
+		if got, err := ParseWeeks(in); err == nil {
+			t.Errorf("ParseWeeks(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func span(from, to int) []int {
+	var out []int
+	for i := from; i <= to; i++ {
+		out = append(out, i)
+	}
+	return out
+}
@@ -44,6 +44,11 @@ func SortTerms(codes []string, now time.Time) []Term {
 	return terms
 }
 
+// CurrentTerm 根据日期推算当前学期代码
+func CurrentTerm(now time.Time) string {
+	return guessCurrentTerm(now)
+}
+
 func guessCurrentTerm(now time.Time) string {
 	year := now.Year()
 	month := int(now.Month())
@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TravelWarning 前后两节课地点不同，课间来不及赶到
+// A、B 为课程在切片中的下标（A 在前），Weeks 为出现该情况的周次
+type TravelWarning struct {
+	A     int   `json:"a"`
+	B     int   `json:"b"`
+	Day   int   `json:"day"`
+	Weeks []int `json:"weeks"`
+	Gap   int   `json:"gap"`  // 课间分钟数
+	Need  int   `json:"need"` // 所需通行分钟数
+	From  Place `json:"from"`
+	To    Place `json:"to"`
+}
+
+// TravelMinutes 返回两地之间的通行时间，同一地点或无法判断时为 0
+// 先查 "校区/楼" 之间的时间，再查校区之间的时间，不同校区未列出时使用 CampusTravel
+func (t *LocationTable) TravelMinutes(a, b Place) int {
+	if a.Online || b.Online || a.TBD || b.TBD {
+		return 0
+	}
+	if m, ok := t.lookupTravel(a.Campus+"/"+a.Building, b.Campus+"/"+b.Building); ok {
+		return m
+	}
+	if a.Campus == "" || b.Campus == "" {
+		return 0
+	}
+	if m, ok := t.lookupTravel(a.Campus, b.Campus); ok {
+		return m
+	}
+	if a.Campus != b.Campus {
+		return t.CampusTravel
+	}
+	return 0
+}
+
+func (t *LocationTable) lookupTravel(a, b string) (int, bool) {
+	if a == b {
+		return 0, false
+	}
+	if m, ok := t.Travel[a][b]; ok {
+		return m, true
+	}
+	m, ok := t.Travel[b][a]
+	return m, ok
+}
+
+// FindTravelWarnings 逐周检查同一天相邻的两节课，课间短于两地通行时间时给出提示
+// 同一对课程在同一天的情况合并为一条，按星期、节次排序
+func FindTravelWarnings(courses []Course, table *LocationTable) []TravelWarning {
+	if table == nil {
+		table = DefaultLocationTable()
+	}
+	type block struct {
+		idx, begin, end int
+	}
+	days := make(map[[2]int][]block) // (周, 星期) → 当天的课
+	places := make([]Place, len(courses))
+	for i, c := range courses {
+		places[i] = ParseLocation(c.Location, table)
+		day, begin, end, err := c.Slot()
+		if err != nil || end > len(SectionTimes) {
+			continue
+		}
+		weeks, err := ParseWeeks(c.Weeks)
+		if err != nil {
+			continue
+		}
+		for _, w := range weeks {
+			k := [2]int{w, day}
+			days[k] = append(days[k], block{i, begin, end})
+		}
+	}
+
+	type pairKey struct{ a, b, day int }
+	found := make(map[pairKey]*TravelWarning)
+	for k, blocks := range days {
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i].begin < blocks[j].begin })
+		for i := 1; i < len(blocks); i++ {
+			prev, next := blocks[i-1], blocks[i]
+			if next.begin <= prev.end {
+				continue // 时间冲突另行报告
+			}
+			need := table.TravelMinutes(places[prev.idx], places[next.idx])
+			// 两个校区的作息时间可能不同，各按所在校区计算
+			gap := clockMinutes(table.Times(places[next.idx].Campus)[next.begin-1].Begin) -
+				clockMinutes(table.Times(places[prev.idx].Campus)[prev.end-1].End)
+			if need == 0 || gap >= need {
+				continue
+			}
+			pk := pairKey{prev.idx, next.idx, k[1]}
+			w := found[pk]
+			if w == nil {
+				w = &TravelWarning{A: prev.idx, B: next.idx, Day: k[1], Gap: gap, Need: need,
+					From: places[prev.idx], To: places[next.idx]}
+				found[pk] = w
+			}
+			w.Weeks = append(w.Weeks, k[0])
+		}
+	}
+
+	warnings := make([]TravelWarning, 0, len(found))
+	for _, w := range found {
+		sort.Ints(w.Weeks)
+		warnings = append(warnings, *w)
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		a, b := warnings[i], warnings[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.A != b.A {
+			return a.A < b.A
+		}
+		return a.B < b.B
+	})
+	return warnings
+}
+
+// Describe 返回可读的中文描述，如 "周一（第1-16周）: 高等数学 小营校区 → 英语 沙河校区，课间 5 分钟，路上约 60 分钟"
+func (w TravelWarning) Describe(courses []Course) string {
+	return fmt.Sprintf("%s（第%s周）: %s %s → %s %s，课间 %d 分钟，路上约 %d 分钟",
+		WeekdayName(w.Day), FormatWeeks(w.Weeks), courses[w.A].Name, w.From, courses[w.B].Name, w.To, w.Gap, w.Need)
+}
@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindTravelWarnings(t *testing.T) {
+	xiaoying := Place{Campus: "小营校区", Building: "3", Room: "101"}
+	shahe := Place{Campus: "沙河校区", Building: "1", Room: "201"}
+
+	// 沙河校区内 1 号楼到 5 号楼需要 20 分钟，小营和沙河之间 40 分钟
+	custom := DefaultLocationTable()
+	custom.Travel = map[string]map[string]int{
+		"沙河校区/1": {"沙河校区/5": 20},
+		"小营校区":   {"沙河校区": 40},
+	}
+
+	tests := []struct {
+		name    string
+		courses []Course
+		table   *LocationTable
+		want    []TravelWarning
+	}{
+		{
+			name: "同一校区不提示",
+			courses: []Course{
+				course("高数", "1", "1", "2", "1-16", "小营3-101"),
+				course("英语", "1", "3", "4", "1-16", "小营校区1-101"),
+			},
+		},
+		{
+			// 第 2、3 节之间课间 15 分钟
+			name: "跨校区课间不足",
+			courses: []Course{
+				course("高数", "1", "1", "2", "1-8", "小营3-101"),
+				course("英语", "1", "3", "4", "5-16", "沙河1-201"),
+			},
+			want: []TravelWarning{{A: 0, B: 1, Day: 1, Weeks: []int{5, 6, 7, 8}, Gap: 15, Need: 60, From: xiaoying, To: shahe}},
+		},
+		{
+			// 第 5、6 节之间午休 45 分钟
+			name: "午休足够赶路",
+			courses: []Course{
+				course("高数", "2", "4", "5", "1-16", "小营3-101"),
+				course("英语", "2", "6", "7", "1-16", "沙河1-201"),
+			},
+			table: custom,
+		},
+		{
+			name: "默认跨校区 60 分钟，午休也不够",
+			courses: []Course{
+				course("高数", "2", "4", "5", "1", "小营3-101"),
+				course("英语", "2", "6", "7", "1", "沙河1-201"),
+			},
+			want: []TravelWarning{{A: 0, B: 1, Day: 2, Weeks: []int{1}, Gap: 45, Need: 60, From: xiaoying, To: shahe}},
+		},
+		{
+			name: "同校区不同楼按楼之间的时间",
+			courses: []Course{
+				course("英语", "3", "3", "4", "1", "沙河5-101"),
+				course("高数", "3", "1", "2", "1", "沙河1-201"),
+			},
+			table: custom,
+			want: []TravelWarning{{A: 1, B: 0, Day: 3, Weeks: []int{1}, Gap: 15, Need: 20,
+				From: shahe, To: Place{Campus: "沙河校区", Building: "5", Room: "101"}}},
+		},
+		{
+			name: "中间隔着其他课时只看相邻的两节",
+			courses: []Course{
+				course("高数", "4", "1", "2", "1", "小营3-101"),
+				course("物理", "4", "3", "4", "1", "小营3-101"),
+				course("英语", "4", "8", "9", "1", "沙河1-201"),
+			},
+		},
+		{
+			name: "线上课和待定地点不提示",
+			courses: []Course{
+				course("高数", "5", "1", "2", "1", "小营3-101"),
+				course("英语", "5", "3", "4", "1", "腾讯会议"),
+				course("物理", "5", "5", "5", "1", "沙河1-201"),
+				course("化学", "5", "6", "7", "1", "待定"),
+			},
+		},
+		{
+			name: "时间冲突不重复提示",
+			courses: []Course{
+				course("高数", "1", "1", "3", "1", "小营3-101"),
+				course("英语", "1", "3", "4", "1", "沙河1-201"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := FindTravelWarnings(tt.courses, tt.table)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %+v\n want %+v", tt.name, got, tt.want)
+		}
+	}
+}
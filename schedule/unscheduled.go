@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Unscheduled 没有固定上课时间的课程（教务系统中的"未安排"，如实践课、集中授课）
+type Unscheduled struct {
+	Name    string  `json:"name"`
+	Code    string  `json:"code,omitempty"`
+	Teacher string  `json:"teacher,omitempty"`
+	Credits float64 `json:"credits,omitempty"`
+	Weeks   string  `json:"weeks,omitempty"`
+	Remark  string  `json:"remark,omitempty"`
+}
+
+// unscheduledKeys 未安排课程各字段可能使用的字段名
+var unscheduledKeys = struct {
+	name, teacher, weeks, remark []string
+}{
+	name:    []string{"courseName", "kcm", "KCM", "kcmc", "KCMC"},
+	teacher: []string{"teacherName", "teachers", "skjs", "SKJS", "jsxm", "JSXM"},
+	weeks:   []string{"weeks", "zcmc", "ZCMC", "weekName"},
+	remark:  []string{"remark", "bz", "BZ", "xkbz", "XKBZ", "notes"},
+}
+
+// String 返回单行描述，如 "专业实习（张三 · 2 学分 · 第17-18周）"
+func (u Unscheduled) String() string {
+	var parts []string
+	if u.Teacher != "" {
+		parts = append(parts, u.Teacher)
+	}
+	if u.Credits > 0 {
+		parts = append(parts, strconv.FormatFloat(u.Credits, 'f', -1, 64)+" 学分")
+	}
+	if u.Weeks != "" {
+		parts = append(parts, "第"+u.Weeks+"周")
+	}
+	if len(parts) == 0 {
+		return u.Name
+	}
+	return u.Name + "（" + strings.Join(parts, " · ") + "）"
+}
+
+// ParseUnscheduled 解析一条未安排课程记录
+// 有 weeksAndTeachers 字段时与已排课程一样解析周数和教师
+func ParseUnscheduled(raw map[string]interface{}) Unscheduled {
+	var c Course
+	parseTeaching(&c, getStr(raw, "weeksAndTeachers", ""))
+	u := Unscheduled{
+		Name:    firstStr(raw, unscheduledKeys.name),
+		Code:    firstStr(raw, metaKeys.code),
+		Teacher: c.Teacher,
+		Weeks:   c.Weeks,
+		Remark:  firstStr(raw, unscheduledKeys.remark),
+	}
+	if u.Name == "" {
+		u.Name = "无"
+	}
+	if u.Teacher == "" {
+		u.Teacher = firstStr(raw, unscheduledKeys.teacher)
+	}
+	if u.Weeks == "" {
+		u.Weeks = cleanWeeks(firstStr(raw, unscheduledKeys.weeks))
+	}
+	if v := firstStr(raw, metaKeys.credits); v != "" {
+		u.Credits, _ = strconv.ParseFloat(v, 64)
+	}
+	return u
+}
+
+// ParseAllUnscheduled 批量解析未安排课程
+func ParseAllUnscheduled(rawList []map[string]interface{}) []Unscheduled {
+	if len(rawList) == 0 {
+		return nil
+	}
+	list := make([]Unscheduled, 0, len(rawList))
+	for _, raw := range rawList {
+		list = append(list, ParseUnscheduled(raw))
+	}
+	return list
+}
+
+// firstStr 按顺序返回第一个非空字段值
+func firstStr(raw map[string]interface{}, keys []string) string {
+	for _, k := range keys {
+		if v := getStr(raw, k, ""); v != "" {
+			return v
+		}
+	}
+	return ""
+}
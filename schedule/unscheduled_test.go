@@ -0,0 +1,126 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseScheduleResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		arranged    []string
+		notArranged []string
+	}{
+		{
+			name:        "arrangedList 和 notArrangeList",
+			body:        `{"datas":{"arrangedList":[{"courseName":"高数"}],"notArrangeList":[{"courseName":"专业实习"},{"KCM":"毕业设计"}]}}`,
+			arranged:    []string{"高数"},
+			notArranged: []string{"专业实习", "毕业设计"},
+		},
+		{
+			name:     "旧接口的 list",
+			body:     `{"datas":{"list":[{"courseName":"高数"},{"courseName":"英语"}]}}`,
+			arranged: []string{"高数", "英语"},
+		},
+		{
+			name:     "data.rows",
+			body:     `{"data":{"rows":[{"courseName":"高数"}]}}`,
+			arranged: []string{"高数"},
+		},
+		{
+			name:        "未安排列表的其他字段名",
+			body:        `{"datas":{"arrangedList":[],"unArrangedList":[{"courseName":"慕课"}]}}`,
+			notArranged: []string{"慕课"},
+		},
+		{
+			name:        "只有未安排课程",
+			body:        `{"datas":{"notArrangedList":[{"courseName":"专业实习"}]}}`,
+			notArranged: []string{"专业实习"},
+		},
+		{
+			name:     "跳过不是对象的记录",
+			body:     `{"datas":{"arrangedList":[{"courseName":"高数"},"x",1],"noArrangeList":[null]}}`,
+			arranged: []string{"高数"},
+		},
+	}
+	for _, tt := range tests {
+		data, err := ParseScheduleResponse([]byte(tt.body))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := recordNames(data.Arranged); !reflect.DeepEqual(got, tt.arranged) {
+			t.Errorf("%s: arranged = %q, want %q", tt.name, got, tt.arranged)
+		}
+		if got := recordNames(data.NotArranged); !reflect.DeepEqual(got, tt.notArranged) {
+			t.Errorf("%s: notArranged = %q, want %q", tt.name, got, tt.notArranged)
+		}
+	}
+
+	for _, body := range []string{``, `{`, `{}`, `{"datas":{"arrangedList":[]}}`, `{"datas":{"arrangedList":[],"notArrangeList":[]}}`} {
+		if data, err := ParseScheduleResponse([]byte(body)); err == nil {
+			t.Errorf("ParseScheduleResponse(%q) = %+v, want error", body, data)
+		}
+	}
+}
+
+func recordNames(records []map[string]interface{}) []string {
+	var out []string
+	for _, r := range records {
+		out = append(out, firstStr(r, unscheduledKeys.name))
+	}
+	return out
+}
+
+func TestParseUnscheduled(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		want Unscheduled
+	}{
+		{
+			name: "weeksAndTeachers 与已排课程一样解析",
+			raw: map[string]interface{}{
+				"courseName": "专业实习", "weeksAndTeachers": "17-18周/李四[讲师]",
+				"KCH": "B100", "XF": 2, "BZ": "集中实习，地点另行通知",
+			},
+			want: Unscheduled{Name: "专业实习", Code: "B100", Teacher: "李四", Credits: 2, Weeks: "17-18", Remark: "集中实习，地点另行通知"},
+		},
+		{
+			name: "教务系统的大写字段名",
+			raw:  map[string]interface{}{"KCM": "毕业设计", "SKJS": "王五", "XF": "8.0", "ZCMC": "第1-16周"},
+			want: Unscheduled{Name: "毕业设计", Teacher: "王五", Credits: 8, Weeks: "1-16"},
+		},
+		{
+			name: "没有课程名",
+			raw:  map[string]interface{}{"remark": "待定"},
+			want: Unscheduled{Name: "无", Remark: "待定"},
+		},
+	}
+	for _, tt := range tests {
+		if got := ParseUnscheduled(tt.raw); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %+v\n want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := ParseAllUnscheduled(nil); got != nil {
+		t.Errorf("ParseAllUnscheduled(nil) = %+v, want nil", got)
+	}
+}
+
+func TestUnscheduledString(t *testing.T) {
+	tests := []struct {
+		in   Unscheduled
+		want string
+	}{
+		{Unscheduled{Name: "专业实习", Teacher: "张三", Credits: 2, Weeks: "17-18"}, "专业实习（张三 · 2 学分 · 第17-18周）"},
+		{Unscheduled{Name: "慕课", Credits: 1.5}, "慕课（1.5 学分）"},
+		{Unscheduled{Name: "毕业设计"}, "毕业设计"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("String(%+v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bistu-wakeup/bistu-wakeup/auth"
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// 非交互场景下用于自动重新登录的环境变量
+const (
+	envUsername = "BISTU_USERNAME"
+	envPassword = "BISTU_PASSWORD"
+)
+
+// session 为 watch / serve 等长期运行的命令维持教务系统登录状态
+// 登录方式依次尝试：--cookie、已保存的会话、环境变量中的学号密码
+type session struct {
+	client  *auth.Client
+	fetcher *schedule.Fetcher
+	store   *cache.Store
+	cookie  string
+	user    *schedule.UserInfo
+}
+
+func newSession(cookie string, store *cache.Store) (*session, error) {
+	client, err := auth.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("初始化失败: %w", err)
+	}
+	return &session{
+		client:  client,
+		fetcher: &schedule.Fetcher{Client: client.HTTP},
+		store:   store,
+		cookie:  cookie,
+	}, nil
+}
+
+// login 建立登录状态并获取用户信息
+func (s *session) login() error {
+	if s.cookie != "" {
+		if err := s.client.CookieLogin(schedule.BaseURL, s.cookie); err != nil {
+			return err
+		}
+		return s.loadUser()
+	}
+
+	if err := s.client.LoadSession(s.store.SessionFile()); err == nil {
+		if err := s.loadUser(); err == nil {
+			return nil
+		} else if !errors.Is(err, schedule.ErrSessionExpired) {
+			return err
+		}
+	}
+
+	username, password := os.Getenv(envUsername), os.Getenv(envPassword)
+	if username == "" || password == "" {
+		return fmt.Errorf("没有可用的登录状态，请先运行一次导出完成登录，或设置环境变量 %s / %s", envUsername, envPassword)
+	}
+	if err := s.client.CASLogin(username, password); err != nil {
+		return err
+	}
+	if err := s.client.SaveSession(s.store.SessionFile()); err != nil {
+		return err
+	}
+	return s.loadUser()
+}
+
+func (s *session) loadUser() error {
+	user, err := s.fetcher.FetchUserInfo()
+	if err != nil {
+		return err
+	}
+	if user.StudentID == "" {
+		return schedule.ErrSessionExpired
+	}
+	s.user = user
+	return nil
+}
+
+// fetch 获取课表并写入缓存，返回新条目和之前的缓存（可能为 nil）
+// 会话失效时自动重新登录并重试一次
+func (s *session) fetch(term string) (*cache.Entry, *cache.Entry, error) {
+	if s.user == nil {
+		if err := s.login(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	data, err := s.fetcher.FetchSchedule(term, s.user.StudentID)
+	if errors.Is(err, schedule.ErrSessionExpired) && s.cookie == "" {
+		s.user = nil
+		if err := s.login(); err != nil {
+			return nil, nil, err
+		}
+		data, err = s.fetcher.FetchSchedule(term, s.user.StudentID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous, err := s.store.Load(s.user.StudentID, term)
+	if err != nil {
+		previous = nil
+	}
+	entry := newEntry(s.user, term, data, previous)
+	if err := s.store.Save(entry); err != nil {
+		return nil, nil, err
+	}
+	return entry, previous, nil
+}
+
+// newEntry 由新获取的原始数据构建缓存条目，沿用之前记住的开学日期
+func newEntry(user *schedule.UserInfo, term string, data *schedule.ScheduleData, previous *cache.Entry) *cache.Entry {
+	entry := &cache.Entry{
+		StudentID:      user.StudentID,
+		UserName:       user.UserName,
+		Term:           term,
+		FetchedAt:      time.Now(),
+		Raw:            data.Arranged,
+		RawNotArranged: data.NotArranged,
+		Unscheduled:    schedule.ParseAllUnscheduled(data.NotArranged),
+	}
+	entry.Courses, entry.Rejected = schedule.ParseRecords(data.Arranged)
+	if previous != nil {
+		entry.StartDate = previous.StartDate
+	}
+	return entry
+}
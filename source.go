@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bistu-wakeup/bistu-wakeup/cache"
+	"github.com/bistu-wakeup/bistu-wakeup/export"
+	"github.com/bistu-wakeup/bistu-wakeup/overrides"
+	"github.com/bistu-wakeup/bistu-wakeup/schedule"
+)
+
+// cacheSource 表示使用本地缓存中最近一次的课表
+const cacheSource = "@cache"
+
+// loadSource 读取课表来源，支持 @cache、WakeUp CSV、json 导出以及原始数据文件
+// 并经过与导出相同的整理步骤，见 prepare
+func loadSource(spec string) (*export.Timetable, error) {
+	t, err := readSource(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepare(t, true); err != nil {
+		return nil, err
+	}
+	// 提示写到标准错误，不影响 --json 等输出
+	if len(t.Rejected) > 0 {
+		printRejected(os.Stderr, t.Rejected)
+	}
+	return t, nil
+}
+
+// prepare 各命令共用的整理步骤（在覆盖规则之后）：合并同一门课相邻的节次，
+// 附上节假日日历（展开为具体日期时按放假和调休调整）和地点别名表
+// 导出、订阅、查看和调课检查都经过这里，同一份课表展开出的上课安排一致
+func prepare(t *export.Timetable, merge bool) error {
+	if merge {
+		t.Courses = schedule.MergeAdjacent(t.Courses)
+	}
+	var err error
+	if t.Calendar, err = loadCalendar(); err != nil {
+		return err
+	}
+	if t.Locations, err = loadLocationTable(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func readSource(spec string) (*export.Timetable, error) {
+	if spec == cacheSource {
+		store, err := cache.Open("")
+		if err != nil {
+			return nil, err
+		}
+		entry, err := store.Latest()
+		if err != nil {
+			return nil, err
+		}
+		t := entryTimetable(entry)
+		if t.Exceptions, err = store.Exceptions(entry.StudentID, entry.Term); err != nil {
+			return nil, err
+		}
+		return withOverrides(t)
+	}
+
+	switch strings.ToLower(filepath.Ext(spec)) {
+	case ".csv":
+		courses, rejected, err := export.ReadCSVFile(spec)
+		if err != nil {
+			return nil, err
+		}
+		return &export.Timetable{Term: offlineTerm("", "", spec), Courses: courses, Rejected: rejected}, nil
+	case ".json":
+		f, err := os.Open(spec)
+		if err != nil {
+			return nil, fmt.Errorf("打开文件失败: %w", err)
+		}
+		t, jsonErr := export.ReadJSON(f)
+		f.Close()
+		if jsonErr == nil {
+			return t, nil
+		}
+		// 不是 json 导出，再尝试按原始数据解析
+		dump, err := schedule.LoadRaw(spec)
+		if err != nil {
+			return nil, err
+		}
+		courses, rejected := schedule.ParseRecords(dump.Items)
+		return withOverrides(&export.Timetable{
+			Term:        offlineTerm("", dump.Term, spec),
+			Courses:     courses,
+			Unscheduled: schedule.ParseAllUnscheduled(dump.NotArranged),
+			Rejected:    rejected,
+		})
+	default:
+		return nil, fmt.Errorf("无法识别的课表文件 %s（支持 .csv / .json 或 %s）", spec, cacheSource)
+	}
+}
+
+// loadOverrides 读取覆盖规则，path 为空时使用默认路径，没有规则文件时返回 nil
+func loadOverrides(path string) (*overrides.Rules, string, error) {
+	if path == "" {
+		if path = overrides.DefaultPath(); path == "" {
+			return nil, "", nil
+		}
+	}
+	rules, err := overrides.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return rules, path, nil
+}
+
+// withOverrides 对来自教务系统的课表应用默认覆盖规则
+// 导出的 CSV / JSON 已经应用过规则，不经过这里
+func withOverrides(t *export.Timetable) (*export.Timetable, error) {
+	rules, _, err := loadOverrides("")
+	if err != nil {
+		return nil, err
+	}
+	t.Courses = rules.Apply(t.Courses)
+	return t, nil
+}
+
+// loadCalendar 读取内置的节假日数据，配置目录下有 holidays.json 时用其覆盖对应年份
+func loadCalendar() (*schedule.Calendar, error) {
+	cal := schedule.BundledCalendar()
+	path, ok := configFile("holidays.json")
+	if !ok {
+		return cal, nil
+	}
+	if err := cal.LoadCalendarFile(path); err != nil {
+		return nil, err
+	}
+	return cal, nil
+}
+
+// loadLocationTable 返回默认的地点别名表，配置目录下有 locations.json 时合并其中的条目
+func loadLocationTable() (*schedule.LocationTable, error) {
+	table := schedule.DefaultLocationTable()
+	path, ok := configFile("locations.json")
+	if !ok {
+		return table, nil
+	}
+	if err := table.LoadLocationTable(path); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// configFile 返回配置目录下的文件路径，文件不存在时 ok 为 false
+func configFile(name string) (path string, ok bool) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	path = filepath.Join(dir, "bistu-wakeup", name)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// shareOnLAN 在局域网内临时提供文件下载，并在终端打印下载地址的二维码
+// 下载成功一次、超时或按 Ctrl+C 后关闭
+func shareOnLAN(path string, timeout time.Duration) error {
+	ip, err := lanIP()
+	if err != nil {
+		return err
+	}
+	token, err := randomToken(12)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(ip.String(), "0"))
+	if err != nil {
+		return fmt.Errorf("启动局域网传输失败: %w", err)
+	}
+
+	name := filepath.Base(path)
+	link := fmt.Sprintf("http://%s/%s/%s", ln.Addr(), token, url.PathEscape(name))
+
+	done := make(chan struct{})
+	var once sync.Once
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{token}/{file}", func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.PathValue("token")), []byte(token)) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			http.Error(w, "文件不存在", http.StatusNotFound)
+			return
+		}
+		defer f.Close()
+		stat, err := f.Stat()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ext := strings.TrimPrefix(filepath.Ext(name), ".")
+		w.Header().Set("Content-Type", contentType(ext))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(name)))
+		cw := &countingWriter{ResponseWriter: w, status: http.StatusOK}
+		http.ServeContent(cw, r, name, stat.ModTime(), f)
+		// 只有 GET 完整下载后才结束传输；HEAD、链接预览、断点续传和 304 不算
+		if r.Method == http.MethodGet && cw.status == http.StatusOK && cw.written == stat.Size() {
+			once.Do(func() { close(done) })
+		}
+	})
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(ln)
+
+	fmt.Printf("  %s %s\n\n", cyan("📱"), bold("用手机扫描二维码下载（手机需连接同一局域网）"))
+	if qr, err := qrcode.New(link, qrcode.Medium); err == nil {
+		for _, line := range strings.Split(strings.TrimRight(qr.ToSmallString(false), "\n"), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	fmt.Printf("\n    %s\n", dim(link))
+	fmt.Printf("    %s\n\n", dim(fmt.Sprintf("等待下载，%s 后自动关闭，Ctrl+C 取消", timeout)))
+
+	// 文件已导出，超时或取消都只是不再提供下载，不算失败
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	select {
+	case <-done:
+		fmt.Printf("  %s %s\n\n", green("✓"), bold("手机已下载完成"))
+	case <-time.After(timeout):
+		fmt.Printf("  %s 等待下载超时，传输已关闭\n\n", yellow("⚠"))
+	case <-ctx.Done():
+		fmt.Printf("\n  %s 已取消传输\n\n", yellow("⚠"))
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdown)
+	return nil
+}
+
+// countingWriter 记录响应状态码和实际写出的字节数
+type countingWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func (w *countingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// lanIP 返回本机的局域网 IPv4 地址，没有私有地址时退而使用其他非回环地址
+func lanIP() (net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("获取网络接口失败: %w", err)
+	}
+	var fallback net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipNet.IP.To4()
+			if ip == nil || ip.IsLinkLocalUnicast() {
+				continue
+			}
+			if ip.IsPrivate() {
+				return ip, nil
+			}
+			if fallback == nil {
+				fallback = ip
+			}
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+	return nil, fmt.Errorf("未找到局域网地址，请确认电脑已连接 Wi-Fi 或有线网络")
+}